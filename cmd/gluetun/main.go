@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,6 +16,7 @@ import (
 	_ "github.com/breml/rootcerts"
 	"github.com/qdm12/dns/pkg/unbound"
 	"github.com/qdm12/gluetun/internal/alpine"
+	"github.com/qdm12/gluetun/internal/blocklist"
 	"github.com/qdm12/gluetun/internal/cli"
 	"github.com/qdm12/gluetun/internal/configuration/sources"
 	"github.com/qdm12/gluetun/internal/configuration/sources/env"
@@ -23,9 +25,13 @@ import (
 	"github.com/qdm12/gluetun/internal/configuration/sources/secrets"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/hooks"
 	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/logs"
+	"github.com/qdm12/gluetun/internal/metrics"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/openvpn"
@@ -68,7 +74,10 @@ func main() {
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 	ctx, cancel := context.WithCancel(background)
 
-	logger := log.New(log.SetLevel(log.LevelInfo))
+	const logsBufferCapacity = 1000
+	logsBuffer := logs.New(logsBufferCapacity)
+	logger := log.New(log.SetLevel(log.LevelInfo),
+		log.SetWriters(os.Stdout, logsBuffer))
 
 	args := os.Args
 	tun := tun.New()
@@ -83,7 +92,8 @@ func main() {
 
 	errorCh := make(chan error)
 	go func() {
-		errorCh <- _main(ctx, buildInfo, args, logger, muxReader, tun, netLinker, cmder, cli)
+		errorCh <- _main(ctx, buildInfo, args, logger, logsBuffer, muxReader,
+			tun, netLinker, cmder, cli)
 	}()
 
 	select {
@@ -123,13 +133,13 @@ var (
 
 //nolint:gocognit,gocyclo,maintidx
 func _main(ctx context.Context, buildInfo models.BuildInformation,
-	args []string, logger log.LoggerInterface, source sources.Source,
-	tun tun.Interface, netLinker netlink.NetLinker, cmder command.RunStarter,
+	args []string, logger log.LoggerInterface, logsBuffer *logs.Buffer,
+	source sources.Source, tun tun.Interface, netLinker netlink.NetLinker, cmder command.RunStarter,
 	cli cli.CLIer) error {
 	if len(args) > 1 { // cli operation
 		switch args[1] {
 		case "healthcheck":
-			return cli.HealthCheck(ctx, source, logger)
+			return cli.HealthCheck(ctx, args[2:], source, logger)
 		case "clientkey":
 			return cli.ClientKey(args[2:])
 		case "openvpnconfig":
@@ -201,6 +211,29 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return err
 	}
 
+	const clientTimeout = 15 * time.Second
+	httpClient := &http.Client{Timeout: clientTimeout}
+
+	// Fetch the host public IP address before the firewall
+	// is enabled and the VPN is up, to detect IP leaks later.
+	// This request goes outside the VPN and reveals the host public
+	// IP address to the public IP service, which is why the leak
+	// check is disabled by default.
+	publicIPFetcher := publicip.NewFetch(httpClient)
+	var hostPublicIP net.IP
+	if *allSettings.Health.LeakCheckPeriod > 0 {
+		const fetchTimeout = 10 * time.Second
+		fetchCtx, fetchCancel := context.WithTimeout(ctx, fetchTimeout)
+		hostPublicIP, err = publicIPFetcher.FetchPublicIP(fetchCtx)
+		fetchCancel()
+		if err != nil {
+			logger.Warn("cannot fetch host public IP address, " +
+				"IP leak detection is disabled: " + err.Error())
+		} else {
+			logger.Info("host public IP address is " + hostPublicIP.String())
+		}
+	}
+
 	if *allSettings.Firewall.Enabled {
 		err = firewallConf.SetEnabled(ctx, true)
 		if err != nil {
@@ -217,6 +250,9 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	allServers := storage.GetServers()
 
+	serversBlocklist := blocklist.New(constants.Blocklist,
+		logger.New(log.SetComponent("blocklist")))
+
 	err = allSettings.Validate(allServers)
 	if err != nil {
 		return err
@@ -230,8 +266,6 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	puid, pgid := int(*allSettings.System.PUID), int(*allSettings.System.PGID)
 
-	const clientTimeout = 15 * time.Second
-	httpClient := &http.Client{Timeout: clientTimeout}
 	// Create configurators
 	alpineConf := alpine.New()
 	ovpnConf := openvpn.New(
@@ -342,16 +376,18 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	otherGroupHandler.Add(pprofHandler)
 	<-pprofReady
 
+	eventsBroker := events.New(logger.New(log.SetComponent("events")))
+
 	portForwardLogger := logger.New(log.SetComponent("port forwarding"))
 	portForwardLooper := portforward.NewLoop(allSettings.VPN.Provider.PortForwarding,
-		httpClient, firewallConf, portForwardLogger)
+		httpClient, firewallConf, portForwardLogger, eventsBroker)
 	portForwardHandler, portForwardCtx, portForwardDone := goshutdown.NewGoRoutineHandler(
 		"port forwarding", goroutine.OptionTimeout(time.Second))
 	go portForwardLooper.Run(portForwardCtx, portForwardDone)
 
 	unboundLogger := logger.New(log.SetComponent("dns over tls"))
 	unboundLooper := dns.NewLoop(dnsConf, allSettings.DNS, httpClient,
-		unboundLogger)
+		unboundLogger, eventsBroker)
 	dnsHandler, dnsCtx, dnsDone := goshutdown.NewGoRoutineHandler(
 		"unbound", goroutine.OptionTimeout(defaultShutdownTimeout))
 	// wait for unboundLooper.Restart or its ticker launched with RunRestartTicker
@@ -365,7 +401,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	publicIPLooper := publicip.NewLoop(httpClient,
 		logger.New(log.SetComponent("ip getter")),
-		allSettings.PublicIP, puid, pgid)
+		allSettings.PublicIP, puid, pgid, eventsBroker)
 	pubIPHandler, pubIPCtx, pubIPDone := goshutdown.NewGoRoutineHandler(
 		"public IP", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go publicIPLooper.Run(pubIPCtx, pubIPDone)
@@ -379,15 +415,56 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	vpnLogger := logger.New(log.SetComponent("vpn"))
 	vpnLooper := vpn.NewLoop(allSettings.VPN, allSettings.Firewall.VPNInputPorts,
 		allServers, ovpnConf, netLinker, firewallConf, routingConf, portForwardLooper,
-		cmder, publicIPLooper, unboundLooper, vpnLogger, httpClient,
-		buildInfo, *allSettings.Version.Enabled)
+		cmder, publicIPLooper, unboundLooper, serversBlocklist, vpnLogger, httpClient,
+		buildInfo, *allSettings.Version.Enabled, eventsBroker)
 	vpnHandler, vpnCtx, vpnDone := goshutdown.NewGoRoutineHandler(
 		"vpn", goroutine.OptionTimeout(time.Second))
 	go vpnLooper.Run(vpnCtx, vpnDone)
 
+	vpnRotationTickerHandler, vpnRotationTickerCtx, vpnRotationTickerDone := goshutdown.NewGoRoutineHandler(
+		"vpn rotation ticker", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go vpnLooper.RunRotationTicker(vpnRotationTickerCtx, vpnRotationTickerDone)
+	tickersGroupHandler.Add(vpnRotationTickerHandler)
+
+	vpnFailbackTickerHandler, vpnFailbackTickerCtx, vpnFailbackTickerDone := goshutdown.NewGoRoutineHandler(
+		"vpn failback ticker", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go vpnLooper.RunFailbackTicker(vpnFailbackTickerCtx, vpnFailbackTickerDone)
+	tickersGroupHandler.Add(vpnFailbackTickerHandler)
+
+	metricsCollector := metrics.New(vpnLooper, netLinker)
+	metricsHandler, metricsCtx, metricsDone := goshutdown.NewGoRoutineHandler(
+		"metrics", goroutine.OptionTimeout(defaultShutdownTimeout))
+	metricsReady := make(chan struct{})
+	go metricsCollector.Run(metricsCtx, eventsBroker, metricsReady, metricsDone)
+	otherGroupHandler.Add(metricsHandler)
+	<-metricsReady
+
+	hooksRunner := hooks.New(allSettings.Hooks,
+		logger.New(log.SetComponent("hooks")))
+	hooksHandler, hooksCtx, hooksDone := goshutdown.NewGoRoutineHandler(
+		"hooks", goroutine.OptionTimeout(defaultShutdownTimeout))
+	hooksReady := make(chan struct{})
+	go hooksRunner.Run(hooksCtx, eventsBroker, hooksReady, hooksDone)
+	otherGroupHandler.Add(hooksHandler)
+	<-hooksReady
+
+	if *allSettings.Metrics.Enabled {
+		allSettings.Metrics.HTTPServer.Logger = logger.New(log.SetComponent("metrics"))
+		metricsServer, err := metrics.NewServer(allSettings.Metrics, metricsCollector)
+		if err != nil {
+			return fmt.Errorf("cannot create metrics server: %w", err)
+		}
+		metricsServerReady := make(chan struct{})
+		metricsServerHandler, metricsServerCtx, metricsServerDone := goshutdown.NewGoRoutineHandler(
+			"metrics server", goroutine.OptionTimeout(defaultShutdownTimeout))
+		go metricsServer.Run(metricsServerCtx, metricsServerReady, metricsServerDone)
+		otherGroupHandler.Add(metricsServerHandler)
+		<-metricsServerReady
+	}
+
 	updaterLooper := updater.NewLooper(allSettings.Updater,
 		allServers, storage, vpnLooper.SetServers, httpClient,
-		logger.New(log.SetComponent("updater")))
+		logger.New(log.SetComponent("updater")), eventsBroker)
 	updaterHandler, updaterCtx, updaterDone := goshutdown.NewGoRoutineHandler(
 		"updater", goroutine.OptionTimeout(defaultShutdownTimeout))
 	// wait for updaterLooper.Restart() or its ticket launched with RunRestartTicker
@@ -401,7 +478,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 
 	httpProxyLooper := httpproxy.NewLoop(
 		logger.New(log.SetComponent("http proxy")),
-		allSettings.HTTPProxy)
+		allSettings.HTTPProxy, eventsBroker)
 	httpProxyHandler, httpProxyCtx, httpProxyDone := goshutdown.NewGoRoutineHandler(
 		"http proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go httpProxyLooper.Run(httpProxyCtx, httpProxyDone)
@@ -414,13 +491,17 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	go shadowsocksLooper.Run(shadowsocksCtx, shadowsocksDone)
 	otherGroupHandler.Add(shadowsocksHandler)
 
-	controlServerAddress := *allSettings.ControlServer.Address
-	controlServerLogging := *allSettings.ControlServer.Log
+	healthLogger := logger.New(log.SetComponent("healthcheck"))
+	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger,
+		vpnLooper, unboundLooper, eventsBroker, metricsCollector, publicIPFetcher, hostPublicIP)
+
 	httpServerHandler, httpServerCtx, httpServerDone := goshutdown.NewGoRoutineHandler(
 		"http server", goroutine.OptionTimeout(defaultShutdownTimeout))
-	httpServer, err := server.New(httpServerCtx, controlServerAddress, controlServerLogging,
+	httpServer, err := server.New(httpServerCtx, allSettings.ControlServer,
 		logger.New(log.SetComponent("http server")),
-		buildInfo, vpnLooper, portForwardLooper, unboundLooper, updaterLooper, publicIPLooper)
+		buildInfo, vpnLooper, portForwardLooper, unboundLooper, updaterLooper, publicIPLooper,
+		httpProxyLooper, shadowsocksLooper, firewallConf, routingConf, eventsBroker,
+		logsBuffer, healthcheckServer)
 	if err != nil {
 		return fmt.Errorf("cannot setup control server: %w", err)
 	}
@@ -429,8 +510,6 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	<-httpServerReady
 	controlGroupHandler.Add(httpServerHandler)
 
-	healthLogger := logger.New(log.SetComponent("healthcheck"))
-	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger, vpnLooper)
 	healthServerHandler, healthServerCtx, healthServerDone := goshutdown.NewGoRoutineHandler(
 		"HTTP health server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go healthcheckServer.Run(healthServerCtx, healthServerDone)
@@ -0,0 +1,164 @@
+// Package blocklist defines a persisted list of VPN servers
+// temporarily excluded from the server selection.
+package blocklist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Entry is a VPN server excluded from the server selection
+// until a certain time. Either its IP or its Hostname is set.
+type Entry struct {
+	IP       net.IP    `json:"ip,omitempty"`
+	Hostname string    `json:"hostname,omitempty"`
+	Reason   string    `json:"reason"`
+	Until    time.Time `json:"until"`
+}
+
+type Blocklist struct {
+	filepath string
+	entries  []Entry
+	mutex    sync.Mutex
+	timeNow  func() time.Time
+}
+
+type Warner interface {
+	Warn(s string)
+}
+
+// New creates a blocklist persisted at the file path given,
+// and loads any existing entry from this file. Since the blocklist
+// is only a cooldown cache, it starts empty and a warning is logged
+// if the file cannot be read or decoded.
+func New(filepath string, logger Warner) (blocklist *Blocklist) {
+	blocklist = &Blocklist{
+		filepath: filepath,
+		timeNow:  time.Now,
+	}
+
+	entries, err := readEntries(filepath)
+	if err != nil {
+		logger.Warn("starting with an empty servers blocklist: " + err.Error())
+		return blocklist
+	}
+	blocklist.entries = entries
+
+	return blocklist
+}
+
+func readEntries(filepath string) (entries []Entry, err error) {
+	data, err := os.ReadFile(filepath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read blocklist file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode blocklist file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Add excludes the server of the connection given from the server
+// selection for the duration given. The server is identified by the
+// connection hostname if it is set, and by its IP address otherwise.
+func (b *Blocklist) Add(connection models.Connection,
+	reason string, duration time.Duration) (err error) {
+	entry := Entry{
+		Reason: reason,
+		Until:  b.timeNow().Add(duration),
+	}
+	if connection.Hostname != "" {
+		entry.Hostname = connection.Hostname
+	} else {
+		entry.IP = connection.IP
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.removeExpired()
+	b.entries = append(b.entries, entry)
+	return b.flush()
+}
+
+// List returns the entries which are not expired.
+func (b *Blocklist) List() (entries []Entry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.removeExpired()
+	entries = make([]Entry, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+// Clear removes all the entries.
+func (b *Blocklist) Clear() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.entries = nil
+	return b.flush()
+}
+
+func (b *Blocklist) removeExpired() {
+	now := b.timeNow()
+	entries := make([]Entry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		if now.Before(entry.Until) {
+			entries = append(entries, entry)
+		}
+	}
+	b.entries = entries
+}
+
+// flush writes the entries to a temporary file in the same directory
+// and renames it over the blocklist file, so a crash while writing
+// cannot leave a partially written blocklist file.
+func (b *Blocklist) flush() (err error) {
+	dir := filepath.Dir(b.filepath)
+	const dirPermission = 0700
+	err = os.MkdirAll(dir, dirPermission)
+	if err != nil {
+		return fmt.Errorf("cannot create blocklist directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode blocklist: %w", err)
+	}
+
+	// The temporary file is created with the 0600 permission.
+	file, err := os.CreateTemp(dir, filepath.Base(b.filepath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary blocklist file: %w", err)
+	}
+	defer os.Remove(file.Name()) // no-op once renamed
+
+	_, err = file.Write(data)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot write temporary blocklist file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close temporary blocklist file: %w", err)
+	}
+
+	err = os.Rename(file.Name(), b.filepath)
+	if err != nil {
+		return fmt.Errorf("cannot replace blocklist file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package blocklist
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Blocklist(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sub", "blocklist.json")
+
+	blocklist := New(path, &testWarner{})
+	assert.Empty(t, blocklist.List())
+
+	now := time.Unix(1000, 0).UTC()
+	blocklist.timeNow = func() time.Time { return now }
+
+	err := blocklist.Add(models.Connection{
+		IP:       net.IPv4(1, 2, 3, 4),
+		Hostname: "a.com",
+	}, "tls error", time.Minute)
+	require.NoError(t, err)
+	err = blocklist.Add(models.Connection{
+		IP: net.IPv4(5, 6, 7, 8),
+	}, "unhealthy", time.Hour)
+	require.NoError(t, err)
+
+	dirInfo, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+
+	expectedEntries := []Entry{
+		{Hostname: "a.com", Reason: "tls error", Until: now.Add(time.Minute)},
+		{IP: net.IPv4(5, 6, 7, 8), Reason: "unhealthy", Until: now.Add(time.Hour)},
+	}
+	assert.Equal(t, expectedEntries, blocklist.List())
+
+	// Entries are loaded back from the file.
+	reloaded := New(path, &testWarner{})
+	reloaded.timeNow = blocklist.timeNow
+	entries := reloaded.List()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a.com", entries[0].Hostname)
+	assert.True(t, net.IPv4(5, 6, 7, 8).Equal(entries[1].IP))
+
+	// First entry expires.
+	now = now.Add(time.Minute)
+	assert.Equal(t, expectedEntries[1:], blocklist.List())
+
+	err = blocklist.Clear()
+	require.NoError(t, err)
+	assert.Empty(t, blocklist.List())
+
+	reloaded = New(path, &testWarner{})
+	assert.Empty(t, reloaded.List())
+}
+
+type testWarner struct {
+	warnings []string
+}
+
+func (w *testWarner) Warn(s string) { w.warnings = append(w.warnings, s) }
+
+func Test_New_corruptFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "blocklist.json")
+	err := os.WriteFile(path, []byte(`[{"ip":"1.2.3.4","rea`), 0600)
+	require.NoError(t, err)
+
+	warner := &testWarner{}
+	blocklist := New(path, warner)
+
+	assert.Empty(t, blocklist.List())
+	assert.Equal(t, []string{"starting with an empty servers blocklist: " +
+		"cannot decode blocklist file: unexpected end of JSON input"},
+		warner.warnings)
+
+	// The corrupt file is replaced on the next change.
+	err = blocklist.Add(models.Connection{Hostname: "a.com"}, "tls error", time.Minute)
+	require.NoError(t, err)
+	reloaded := New(path, warner)
+	assert.Len(t, reloaded.List(), 1)
+	assert.Len(t, warner.warnings, 1)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_Blocklist_FilterConnections(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		entries     []Entry
+		connections []models.Connection
+		filtered    []models.Connection
+	}{
+		"no entry": {
+			connections: []models.Connection{{Hostname: "a.com"}},
+			filtered:    []models.Connection{{Hostname: "a.com"}},
+		},
+		"blocked hostname": {
+			entries: []Entry{{Hostname: "a.com"}},
+			connections: []models.Connection{
+				{Hostname: "a.com", IP: net.IPv4(1, 1, 1, 1)},
+				{Hostname: "a.com", IP: net.IPv4(2, 2, 2, 2)},
+				{Hostname: "b.com", IP: net.IPv4(3, 3, 3, 3)},
+			},
+			filtered: []models.Connection{
+				{Hostname: "b.com", IP: net.IPv4(3, 3, 3, 3)},
+			},
+		},
+		"blocked IP address": {
+			entries: []Entry{{IP: net.IPv4(1, 1, 1, 1)}},
+			connections: []models.Connection{
+				{IP: net.IPv4(1, 1, 1, 1)},
+				{Hostname: "a.com", IP: net.IPv4(1, 1, 1, 1)},
+				{IP: net.IPv4(2, 2, 2, 2)},
+			},
+			filtered: []models.Connection{{IP: net.IPv4(2, 2, 2, 2)}},
+		},
+		"all blocked": {
+			entries:     []Entry{{IP: net.IPv4(1, 1, 1, 1)}},
+			connections: []models.Connection{{IP: net.IPv4(1, 1, 1, 1)}},
+			filtered:    []models.Connection{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Unix(1000, 0)
+			for i := range testCase.entries {
+				testCase.entries[i].Until = now.Add(time.Hour)
+			}
+			blocklist := &Blocklist{
+				entries: testCase.entries,
+				timeNow: func() time.Time { return now },
+			}
+
+			filtered := blocklist.FilterConnections(testCase.connections)
+
+			assert.Equal(t, testCase.filtered, filtered)
+			for _, connection := range testCase.connections {
+				blocked := !containsConnection(filtered, connection)
+				assert.Equal(t, blocked, blocklist.Blocked(connection))
+			}
+		})
+	}
+}
+
+func containsConnection(connections []models.Connection,
+	connection models.Connection) bool {
+	for _, c := range connections {
+		if c.Hostname == connection.Hostname && c.IP.Equal(connection.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package blocklist
+
+import (
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// FilterConnections returns the connections given without the
+// connections having a blocked hostname or a blocked IP address.
+func (b *Blocklist) FilterConnections(connections []models.Connection) (
+	filtered []models.Connection) {
+	entries := b.List()
+	if len(entries) == 0 {
+		return connections
+	}
+
+	filtered = make([]models.Connection, 0, len(connections))
+	for _, connection := range connections {
+		if !connectionIsBlocked(connection, entries) {
+			filtered = append(filtered, connection)
+		}
+	}
+	return filtered
+}
+
+// Blocked returns true if the connection given has
+// a blocked hostname or a blocked IP address.
+func (b *Blocklist) Blocked(connection models.Connection) bool {
+	return connectionIsBlocked(connection, b.List())
+}
+
+func connectionIsBlocked(connection models.Connection, entries []Entry) bool {
+	for _, entry := range entries {
+		switch {
+		case entry.Hostname != "" && entry.Hostname == connection.Hostname,
+			entry.IP != nil && entry.IP.Equal(connection.IP):
+			return true
+		}
+	}
+	return false
+}
@@ -2,41 +2,110 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/sources"
 	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpserver"
 )
 
 type HealthChecker interface {
-	HealthCheck(ctx context.Context, source sources.Source, warner Warner) error
+	HealthCheck(ctx context.Context, args []string, source sources.Source, warner Warner) error
 }
 
-func (c *CLI) HealthCheck(ctx context.Context, source sources.Source, warner Warner) error {
+var ErrHealthEndpointNotValid = errors.New("health endpoint is not valid")
+
+func (c *CLI) HealthCheck(ctx context.Context, args []string,
+	source sources.Source, warner Warner) error {
+	flagSet := flag.NewFlagSet("healthcheck", flag.ExitOnError)
+	endpoint := flagSet.String("endpoint", "health",
+		"health server endpoint to check, which can be 'health', 'livez' or 'readyz'")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	var path string
+	switch *endpoint {
+	case "health":
+	case "livez", "readyz":
+		path = "/" + *endpoint
+	default:
+		return fmt.Errorf("%w: %s", ErrHealthEndpointNotValid, *endpoint)
+	}
+
 	// Extract the health server port from the configuration.
 	config, err := source.ReadHealth()
 	if err != nil {
 		return err
 	}
 
+	config.SetDefaults()
+
 	err = config.Validate()
 	if err != nil {
 		return err
 	}
 
-	_, port, err := net.SplitHostPort(config.ServerAddress)
-	if err != nil {
-		return err
+	const timeout = 10 * time.Second
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	scheme := "http"
+	if config.ServerTLS.Enabled() {
+		scheme = "https"
+		transport.TLSClientConfig, err = makeHealthTLSConfig(config.ServerTLS)
+		if err != nil {
+			return err
+		}
 	}
 
-	const timeout = 10 * time.Second
-	httpClient := &http.Client{Timeout: timeout}
+	var host string
+	if socketPath, ok := httpserver.UnixSocketPath(config.ServerAddress); ok {
+		host = "unix"
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			dialer := &net.Dialer{}
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	} else {
+		_, port, err := net.SplitHostPort(config.ServerAddress)
+		if err != nil {
+			return err
+		}
+		host = "127.0.0.1:" + port
+	}
+
+	httpClient := &http.Client{Timeout: timeout, Transport: transport}
 	client := healthcheck.NewClient(httpClient)
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	url := "http://127.0.0.1:" + port
+	url := scheme + "://" + host + path
 	return client.Check(ctx, url)
 }
+
+// makeHealthTLSConfig returns the TLS configuration to query the local
+// health server over HTTPS. The server certificate is not verified since
+// the server is the program itself. If the server verifies client
+// certificates, the server certificate and key are presented as client
+// certificate, so the server certificate must be signed by one of the
+// client certificate authorities and allow client authentication.
+func makeHealthTLSConfig(tlsSettings httpserver.TLS) (config *tls.Config, err error) {
+	config = &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if tlsSettings.ClientCAFilepath != "" {
+		certificate, err := tls.LoadX509KeyPair(tlsSettings.CertFilepath, tlsSettings.KeyFilepath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS key pair: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/blocklist"
 	"github.com/qdm12/gluetun/internal/configuration/sources"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/provider"
@@ -36,7 +37,9 @@ func (c *CLI) OpenvpnConfig(logger OpenvpnConfigLogger, source sources.Source) e
 		return err
 	}
 
-	providerConf := provider.New(*allSettings.VPN.Provider.Name, allServers, time.Now)
+	serversBlocklist := blocklist.New(constants.Blocklist, logger)
+	providerConf := provider.New(*allSettings.VPN.Provider.Name, allServers,
+		nil, serversBlocklist, time.Now)
 	connection, err := providerConf.GetConnection(allSettings.VPN.Provider.ServerSelection)
 	if err != nil {
 		return err
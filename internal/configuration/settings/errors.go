@@ -4,10 +4,24 @@ import "errors"
 
 var (
 	ErrCityNotValid                    = errors.New("the city specified is not valid")
+	ErrControlServerAPIKeyDuplicate    = errors.New("API key is used by more than one role")
+	ErrControlServerPasswordMissing    = errors.New("password is missing")
 	ErrControlServerPrivilegedPort     = errors.New("cannot use privileged port without running as root")
+	ErrControlServerUnixSocketPath     = errors.New("control server unix socket path is not absolute")
+	ErrControlServerUserDuplicate      = errors.New("user is used by more than one role")
+	ErrControlServerUserMissing        = errors.New("user is missing")
 	ErrCountryNotValid                 = errors.New("the country specified is not valid")
 	ErrFilepathMissing                 = errors.New("filepath is missing")
 	ErrFirewallZeroPort                = errors.New("cannot have a zero port to block")
+	ErrHealthHandshakeTimeoutTooSmall  = errors.New("health Wireguard handshake timeout is too small")
+	ErrHealthLeakCheckPeriodTooSmall   = errors.New("health IP leak check period is too small")
+	ErrHealthTargetAddressNotSet       = errors.New("health target address is not set")
+	ErrHealthTargetStatusNotValid      = errors.New("health target expected HTTP status is not valid")
+	ErrHealthTargetTypeNotValid        = errors.New("health target probe type is not valid")
+	ErrHealthTargetURLNotValid         = errors.New("health target URL is not valid")
+	ErrHealthTargetsPolicyNotValid     = errors.New("health targets policy is not valid")
+	ErrHealthUnixSocketPath            = errors.New("health server unix socket path is not absolute")
+	ErrHookTimeoutTooSmall             = errors.New("hook timeout is too small")
 	ErrHostnameNotValid                = errors.New("the hostname specified is not valid")
 	ErrISPNotValid                     = errors.New("the ISP specified is not valid")
 	ErrMissingValue                    = errors.New("missing value")
@@ -22,15 +36,21 @@ var (
 	ErrOpenVPNUserIsEmpty              = errors.New("user is empty")
 	ErrOpenVPNVerbosityIsOutOfBounds   = errors.New("verbosity value is out of bounds")
 	ErrOpenVPNVersionIsNotValid        = errors.New("version is not valid")
+	ErrPortForwardClientURLNotValid    = errors.New("port forwarding client URL is not valid")
 	ErrPortForwardingEnabled           = errors.New("port forwarding cannot be enabled")
 	ErrPublicIPPeriodTooShort          = errors.New("public IP address check period is too short")
 	ErrRegionNotValid                  = errors.New("the region specified is not valid")
+	ErrSelectionStrategyNotValid       = errors.New("server selection strategy is not valid")
 	ErrServerAddressNotValid           = errors.New("server listening address is not valid")
 	ErrSystemPGIDNotValid              = errors.New("process group id is not valid")
 	ErrSystemPUIDNotValid              = errors.New("process user id is not valid")
 	ErrSystemTimezoneNotValid          = errors.New("timezone is not valid")
 	ErrUpdaterPeriodTooSmall           = errors.New("VPN server data updater period is too small")
+	ErrVPNFailoverThresholdTooSmall    = errors.New("VPN failover threshold is too small")
+	ErrVPNProfileNameDuplicate         = errors.New("VPN profile name is used more than once")
+	ErrVPNProfileNameNotValid          = errors.New("VPN profile name is not valid")
 	ErrVPNProviderNameNotValid         = errors.New("VPN provider name is not valid")
+	ErrVPNRotationPeriodTooSmall       = errors.New("VPN server rotation period is too small")
 	ErrVPNTypeNotValid                 = errors.New("VPN type is not valid")
 	ErrWireguardEndpointIPNotSet       = errors.New("endpoint IP is not set")
 	ErrWireguardEndpointPortNotAllowed = errors.New("endpoint port is not allowed")
@@ -0,0 +1,204 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gotree"
+)
+
+// PrimaryProfileName is the name of the VPN profile
+// defined by the VPN settings themselves.
+const PrimaryProfileName = "primary"
+
+// Failover contains settings to fail over to other VPN
+// profiles when the primary VPN settings keep failing.
+type Failover struct {
+	// Profiles is the ordered list of VPN profiles to fail
+	// over to, after the profile defined by the VPN settings.
+	// It can be empty to disable failing over.
+	Profiles []VPNProfile
+	// Threshold is the duration a VPN profile can keep
+	// failing to connect or stay unhealthy before failing
+	// over to the next profile. It cannot be nil in the
+	// internal state.
+	Threshold *time.Duration
+	// ReturnPeriod is the period to try returning to the
+	// primary profile when using another profile. It is
+	// disabled if set to 0, and cannot be nil in the
+	// internal state.
+	ReturnPeriod *time.Duration
+}
+
+func (f Failover) validate() (err error) {
+	const minThreshold = time.Minute
+	if *f.Threshold < minThreshold {
+		return fmt.Errorf("%w: %s must be larger than %s",
+			ErrVPNFailoverThresholdTooSmall, *f.Threshold, minThreshold)
+	}
+
+	names := make(map[string]struct{}, len(f.Profiles))
+	for _, profile := range f.Profiles {
+		switch profile.Name {
+		case "", PrimaryProfileName:
+			return fmt.Errorf("%w: %q", ErrVPNProfileNameNotValid, profile.Name)
+		}
+		if _, exists := names[profile.Name]; exists {
+			return fmt.Errorf("%w: %s", ErrVPNProfileNameDuplicate, profile.Name)
+		}
+		names[profile.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+func (f *Failover) copy() (copied Failover) {
+	copied = Failover{
+		Threshold:    helpers.CopyDurationPtr(f.Threshold),
+		ReturnPeriod: helpers.CopyDurationPtr(f.ReturnPeriod),
+	}
+	if f.Profiles != nil {
+		copied.Profiles = make([]VPNProfile, len(f.Profiles))
+		for i := range f.Profiles {
+			copied.Profiles[i] = f.Profiles[i].copy()
+		}
+	}
+	return copied
+}
+
+func (f *Failover) mergeWith(other Failover) {
+	if f.Profiles == nil {
+		f.Profiles = other.copy().Profiles
+	}
+	f.Threshold = helpers.MergeWithDuration(f.Threshold, other.Threshold)
+	f.ReturnPeriod = helpers.MergeWithDuration(f.ReturnPeriod, other.ReturnPeriod)
+}
+
+func (f *Failover) overrideWith(other Failover) {
+	if other.Profiles != nil {
+		f.Profiles = other.copy().Profiles
+	}
+	f.Threshold = helpers.OverrideWithDuration(f.Threshold, other.Threshold)
+	f.ReturnPeriod = helpers.OverrideWithDuration(f.ReturnPeriod, other.ReturnPeriod)
+}
+
+func (f *Failover) setDefaults() {
+	for i := range f.Profiles {
+		f.Profiles[i].setDefaults()
+	}
+	const defaultThreshold = 5 * time.Minute
+	f.Threshold = helpers.DefaultDuration(f.Threshold, defaultThreshold)
+	f.ReturnPeriod = helpers.DefaultDuration(f.ReturnPeriod, time.Hour)
+}
+
+func (f Failover) String() string {
+	return f.toLinesNode().String()
+}
+
+func (f Failover) toLinesNode() (node *gotree.Node) {
+	if len(f.Profiles) == 0 {
+		return nil
+	}
+
+	node = gotree.New("Failover settings:")
+	node.Appendf("Failing threshold: %s", *f.Threshold)
+	if *f.ReturnPeriod > 0 {
+		node.Appendf("Return to primary period: %s", *f.ReturnPeriod)
+	} else {
+		node.Appendf("Return to primary period: disabled")
+	}
+
+	profilesNode := node.Appendf("Profiles:")
+	for _, profile := range f.Profiles {
+		profilesNode.AppendNode(profile.toLinesNode())
+	}
+
+	return node
+}
+
+// VPNProfile contains the settings to connect to a VPN
+// provider, to use instead of the ones of the VPN settings.
+type VPNProfile struct {
+	// Name is the name identifying the profile.
+	// It cannot be the empty string in the internal state.
+	Name string
+	// Type is the VPN type and can only be
+	// 'openvpn' or 'wireguard'. It cannot be the
+	// empty string in the internal state.
+	Type      string
+	Provider  Provider
+	OpenVPN   OpenVPN
+	Wireguard Wireguard
+}
+
+func (p *VPNProfile) copy() (copied VPNProfile) {
+	return VPNProfile{
+		Name:      p.Name,
+		Type:      p.Type,
+		Provider:  p.Provider.copy(),
+		OpenVPN:   p.OpenVPN.copy(),
+		Wireguard: p.Wireguard.copy(),
+	}
+}
+
+func (p *VPNProfile) setDefaults() {
+	p.Type = helpers.DefaultString(p.Type, constants.OpenVPN)
+	p.Provider.setDefaults()
+	p.OpenVPN.setDefaults(*p.Provider.Name)
+	p.Wireguard.setDefaults()
+}
+
+func (p VPNProfile) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Profile %s:", p.Name)
+	node.AppendNode(p.Provider.toLinesNode())
+	if p.Type == constants.OpenVPN {
+		node.AppendNode(p.OpenVPN.toLinesNode())
+	} else {
+		node.AppendNode(p.Wireguard.toLinesNode())
+	}
+	return node
+}
+
+// WithProfile returns a copy of the VPN settings where the VPN
+// type, provider, OpenVPN and Wireguard settings are the ones
+// of the profile given.
+func (v VPN) WithProfile(profile VPNProfile) VPN {
+	v.Type = profile.Type
+	v.Provider = profile.Provider
+	v.OpenVPN = profile.OpenVPN
+	v.Wireguard = profile.Wireguard
+	return v
+}
+
+// ProfileNames returns the names of the VPN profiles,
+// starting with the primary profile name.
+func (v VPN) ProfileNames() (names []string) {
+	names = make([]string, 1+len(v.Failover.Profiles))
+	names[0] = PrimaryProfileName
+	for i, profile := range v.Failover.Profiles {
+		names[i+1] = profile.Name
+	}
+	return names
+}
+
+func (v *VPN) validateProfiles(allServers models.AllServers) (err error) {
+	err = v.Failover.validate()
+	if err != nil {
+		return fmt.Errorf("failover settings: %w", err)
+	}
+
+	for i, profile := range v.Failover.Profiles {
+		profileSettings := v.WithProfile(profile)
+		err = profileSettings.validateConnection(allServers)
+		if err != nil {
+			return fmt.Errorf("failover profile %s: %w", profile.Name, err)
+		}
+		// Keep changes made to the provider settings during validation.
+		v.Failover.Profiles[i].Provider = profileSettings.Provider
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package settings
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Failover_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		failover   Failover
+		errWrapped error
+		errMessage string
+	}{
+		"no profile": {
+			failover: Failover{Threshold: durationPtr(time.Minute)},
+		},
+		"threshold too small": {
+			failover:   Failover{Threshold: durationPtr(time.Second)},
+			errWrapped: ErrVPNFailoverThresholdTooSmall,
+			errMessage: "VPN failover threshold is too small: 1s must be larger than 1m0s",
+		},
+		"empty profile name": {
+			failover: Failover{
+				Threshold: durationPtr(time.Minute),
+				Profiles:  []VPNProfile{{}},
+			},
+			errWrapped: ErrVPNProfileNameNotValid,
+			errMessage: `VPN profile name is not valid: ""`,
+		},
+		"primary profile name": {
+			failover: Failover{
+				Threshold: durationPtr(time.Minute),
+				Profiles:  []VPNProfile{{Name: PrimaryProfileName}},
+			},
+			errWrapped: ErrVPNProfileNameNotValid,
+			errMessage: `VPN profile name is not valid: "primary"`,
+		},
+		"duplicate profile name": {
+			failover: Failover{
+				Threshold: durationPtr(time.Minute),
+				Profiles:  []VPNProfile{{Name: "a"}, {Name: "b"}, {Name: "a"}},
+			},
+			errWrapped: ErrVPNProfileNameDuplicate,
+			errMessage: "VPN profile name is used more than once: a",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.failover.validate()
+
+			assert.True(t, errors.Is(err, testCase.errWrapped))
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_VPN_WithProfile(t *testing.T) {
+	t.Parallel()
+
+	vpn := VPN{
+		Type:           "openvpn",
+		Provider:       Provider{Name: stringPtr("mullvad")},
+		RotationPeriod: durationPtr(time.Hour),
+	}
+	profile := VPNProfile{
+		Name:      "backup",
+		Type:      "wireguard",
+		Provider:  Provider{Name: stringPtr("ivpn")},
+		Wireguard: Wireguard{PrivateKey: stringPtr("key")},
+	}
+
+	profileSettings := vpn.WithProfile(profile)
+
+	expected := VPN{
+		Type:           "wireguard",
+		Provider:       Provider{Name: stringPtr("ivpn")},
+		Wireguard:      Wireguard{PrivateKey: stringPtr("key")},
+		RotationPeriod: durationPtr(time.Hour),
+	}
+	assert.Equal(t, expected, profileSettings)
+	assert.Equal(t, "openvpn", vpn.Type)
+	assert.Equal(t, []string{"primary"}, vpn.ProfileNames())
+}
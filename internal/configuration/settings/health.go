@@ -3,8 +3,13 @@ package settings
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/govalid/address"
 )
@@ -12,22 +17,91 @@ import (
 // Health contains settings for the healthcheck and health server.
 type Health struct {
 	// ServerAddress is the listening address
-	// for the health check server.
+	// for the health check server. It can be a
+	// Unix domain socket address such as
+	// unix:/gluetun/health.sock.
 	// It cannot be the empty string in the internal state.
 	ServerAddress string
+	// ServerTLS contains the settings to serve the health
+	// server over HTTPS. TLS is disabled by default.
+	ServerTLS httpserver.TLS
 	// TargetAddress is the address (host or host:port)
-	// to TCP dial to periodically for the health check.
+	// to TCP dial to periodically for the health check,
+	// if no target is set in Targets.
 	// It cannot be the empty string in the internal state.
 	TargetAddress string
-	VPN           HealthyWait
+	// Targets is the list of targets to probe periodically
+	// for the health check. It defaults to a single TCP
+	// target using the TargetAddress, and cannot be empty
+	// in the internal state.
+	Targets []HealthTarget
+	// TargetsPolicy defines how many targets must pass for
+	// the health check to succeed, and can be 'all', 'any' or
+	// a number of targets. It cannot be the empty string in
+	// the internal state.
+	TargetsPolicy string
+	// LeakCheckPeriod is the period to check the public IP
+	// address seen through the VPN differs from the host public
+	// IP address fetched at startup. It defaults to 0 which disables it,
+	// since enabling it fetches the host public IP address outside the
+	// VPN at startup, before the firewall is enabled, revealing the host
+	// IP address to the public IP service and delaying startup by up to
+	// 10 seconds. It cannot be nil in the internal state.
+	LeakCheckPeriod *time.Duration
+	// WireguardHandshakeTimeout is the maximum age of the
+	// latest Wireguard handshake before the tunnel is considered
+	// unhealthy. It is disabled if set to 0, and cannot be nil
+	// in the internal state.
+	WireguardHandshakeTimeout *time.Duration
+	VPN                       HealthyWait
+	Recovery                  HealthRecovery
 }
 
 func (h Health) Validate() (err error) {
-	uid := os.Getuid()
-	_, err = address.Validate(h.ServerAddress,
-		address.OptionListening(uid))
+	if path, ok := httpserver.UnixSocketPath(h.ServerAddress); ok {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("%w: %s", ErrHealthUnixSocketPath, path)
+		}
+	} else {
+		uid := os.Getuid()
+		_, err = address.Validate(h.ServerAddress,
+			address.OptionListening(uid))
+		if err != nil {
+			return fmt.Errorf("server listening address is not valid: %w", err)
+		}
+	}
+
+	err = h.ServerTLS.Validate()
+	if err != nil {
+		return fmt.Errorf("server TLS: %w", err)
+	}
+
+	for i, target := range h.Targets {
+		err = target.validate()
+		if err != nil {
+			return fmt.Errorf("health target %d of %d: %w", i+1, len(h.Targets), err)
+		}
+	}
+
+	_, err = h.RequiredTargets()
 	if err != nil {
-		return fmt.Errorf("server listening address is not valid: %w", err)
+		return err
+	}
+
+	const minLeakCheckPeriod = time.Minute
+	if *h.LeakCheckPeriod > 0 && *h.LeakCheckPeriod < minLeakCheckPeriod {
+		return fmt.Errorf("%w: %s must be larger than %s",
+			ErrHealthLeakCheckPeriodTooSmall, *h.LeakCheckPeriod, minLeakCheckPeriod)
+	}
+
+	// Wireguard renews its handshake every 2 minutes while
+	// traffic flows, so leave some margin above it.
+	const minHandshakeTimeout = 150 * time.Second
+	if *h.WireguardHandshakeTimeout > 0 &&
+		*h.WireguardHandshakeTimeout < minHandshakeTimeout {
+		return fmt.Errorf("%w: %s must be larger than %s",
+			ErrHealthHandshakeTimeoutTooSmall,
+			*h.WireguardHandshakeTimeout, minHandshakeTimeout)
 	}
 
 	err = h.VPN.validate()
@@ -35,14 +109,25 @@ func (h Health) Validate() (err error) {
 		return fmt.Errorf("health VPN settings: %w", err)
 	}
 
+	err = h.Recovery.validate()
+	if err != nil {
+		return fmt.Errorf("health recovery settings: %w", err)
+	}
+
 	return nil
 }
 
 func (h *Health) copy() (copied Health) {
 	return Health{
-		ServerAddress: h.ServerAddress,
-		TargetAddress: h.TargetAddress,
-		VPN:           h.VPN.copy(),
+		ServerAddress:             h.ServerAddress,
+		ServerTLS:                 h.ServerTLS.Copy(),
+		TargetAddress:             h.TargetAddress,
+		Targets:                   copyHealthTargets(h.Targets),
+		TargetsPolicy:             h.TargetsPolicy,
+		LeakCheckPeriod:           helpers.CopyDurationPtr(h.LeakCheckPeriod),
+		WireguardHandshakeTimeout: helpers.CopyDurationPtr(h.WireguardHandshakeTimeout),
+		VPN:                       h.VPN.copy(),
+		Recovery:                  h.Recovery.copy(),
 	}
 }
 
@@ -50,8 +135,17 @@ func (h *Health) copy() (copied Health) {
 // unset field of the receiver settings object.
 func (h *Health) MergeWith(other Health) {
 	h.ServerAddress = helpers.MergeWithString(h.ServerAddress, other.ServerAddress)
+	h.ServerTLS.MergeWith(other.ServerTLS)
 	h.TargetAddress = helpers.MergeWithString(h.TargetAddress, other.TargetAddress)
+	if h.Targets == nil {
+		h.Targets = copyHealthTargets(other.Targets)
+	}
+	h.TargetsPolicy = helpers.MergeWithString(h.TargetsPolicy, other.TargetsPolicy)
+	h.LeakCheckPeriod = helpers.MergeWithDuration(h.LeakCheckPeriod, other.LeakCheckPeriod)
+	h.WireguardHandshakeTimeout = helpers.MergeWithDuration(h.WireguardHandshakeTimeout,
+		other.WireguardHandshakeTimeout)
 	h.VPN.mergeWith(other.VPN)
+	h.Recovery.mergeWith(other.Recovery)
 }
 
 // OverrideWith overrides fields of the receiver
@@ -59,14 +153,38 @@ func (h *Health) MergeWith(other Health) {
 // settings.
 func (h *Health) OverrideWith(other Health) {
 	h.ServerAddress = helpers.OverrideWithString(h.ServerAddress, other.ServerAddress)
+	h.ServerTLS.OverrideWith(other.ServerTLS)
 	h.TargetAddress = helpers.OverrideWithString(h.TargetAddress, other.TargetAddress)
+	if other.Targets != nil {
+		h.Targets = copyHealthTargets(other.Targets)
+	}
+	h.TargetsPolicy = helpers.OverrideWithString(h.TargetsPolicy, other.TargetsPolicy)
+	h.LeakCheckPeriod = helpers.OverrideWithDuration(h.LeakCheckPeriod, other.LeakCheckPeriod)
+	h.WireguardHandshakeTimeout = helpers.OverrideWithDuration(h.WireguardHandshakeTimeout,
+		other.WireguardHandshakeTimeout)
 	h.VPN.overrideWith(other.VPN)
+	h.Recovery.overrideWith(other.Recovery)
 }
 
 func (h *Health) SetDefaults() {
 	h.ServerAddress = helpers.DefaultString(h.ServerAddress, "127.0.0.1:9999")
 	h.TargetAddress = helpers.DefaultString(h.TargetAddress, "cloudflare.com:443")
+	if len(h.Targets) == 0 {
+		h.Targets = []HealthTarget{{
+			Type:    constants.HealthProbeTCP,
+			Address: h.TargetAddress,
+		}}
+	}
+	for i := range h.Targets {
+		h.Targets[i].setDefaults()
+	}
+	h.TargetsPolicy = helpers.DefaultString(h.TargetsPolicy, constants.HealthPolicyAll)
+	h.LeakCheckPeriod = helpers.DefaultDuration(h.LeakCheckPeriod, 0)
+	const defaultHandshakeTimeout = 3 * time.Minute
+	h.WireguardHandshakeTimeout = helpers.DefaultDuration(h.WireguardHandshakeTimeout,
+		defaultHandshakeTimeout)
 	h.VPN.setDefaults()
+	h.Recovery.setDefaults()
 }
 
 func (h Health) String() string {
@@ -76,7 +194,46 @@ func (h Health) String() string {
 func (h Health) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Health settings:")
 	node.Appendf("Server listening address: %s", h.ServerAddress)
-	node.Appendf("Target address: %s", h.TargetAddress)
+	if h.ServerTLS.Enabled() {
+		node.AppendNode(h.ServerTLS.ToLinesNode())
+	}
+	targetsNode := node.Appendf("Targets (%s must pass):", h.TargetsPolicy)
+	for _, target := range h.Targets {
+		targetsNode.Appendf("%s", target)
+	}
+	leakCheck := "disabled"
+	if *h.LeakCheckPeriod > 0 {
+		leakCheck = "every " + h.LeakCheckPeriod.String()
+	}
+	node.Appendf("IP leak check: %s", leakCheck)
+	handshakeTimeout := "disabled"
+	if *h.WireguardHandshakeTimeout > 0 {
+		handshakeTimeout = h.WireguardHandshakeTimeout.String()
+	}
+	node.Appendf("Wireguard handshake timeout: %s", handshakeTimeout)
 	node.AppendNode(h.VPN.toLinesNode("VPN"))
+	node.AppendNode(h.Recovery.toLinesNode())
 	return node
 }
+
+// RequiredTargets returns the number of targets which must pass
+// for the health check to succeed, according to the targets policy.
+func (h Health) RequiredTargets() (required int, err error) {
+	switch h.TargetsPolicy {
+	case constants.HealthPolicyAll:
+		return len(h.Targets), nil
+	case constants.HealthPolicyAny:
+		return 1, nil
+	}
+
+	required, err = strconv.Atoi(h.TargetsPolicy)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q must be %s, %s or a number",
+			ErrHealthTargetsPolicyNotValid, h.TargetsPolicy,
+			constants.HealthPolicyAll, constants.HealthPolicyAny)
+	} else if required < 1 || required > len(h.Targets) {
+		return 0, fmt.Errorf("%w: %d must be between 1 and the number of targets %d",
+			ErrHealthTargetsPolicyNotValid, required, len(h.Targets))
+	}
+	return required, nil
+}
@@ -0,0 +1,87 @@
+package settings
+
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// HealthRecovery contains settings for the escalating
+// recovery actions taken when the VPN is unhealthy.
+type HealthRecovery struct {
+	// Restarts is the number of VPN restarts with the same
+	// server before switching to another server.
+	// It cannot be nil in the internal state.
+	Restarts *uint8
+	// ServerSwitches is the number of switches to another
+	// server before falling back from UDP to TCP, if
+	// TCPFallback is enabled.
+	// It cannot be nil in the internal state.
+	ServerSwitches *uint8
+	// ExcludeDuration is the duration a server switched away
+	// from is excluded from the server selection.
+	// It cannot be nil in the internal state.
+	ExcludeDuration *time.Duration
+	// TCPFallback enables falling back from UDP to TCP for
+	// OpenVPN once all the server switches are exhausted.
+	// It cannot be nil in the internal state.
+	TCPFallback *bool
+}
+
+func (h HealthRecovery) validate() (err error) {
+	return nil
+}
+
+func (h *HealthRecovery) copy() (copied HealthRecovery) {
+	return HealthRecovery{
+		Restarts:        helpers.CopyUint8Ptr(h.Restarts),
+		ServerSwitches:  helpers.CopyUint8Ptr(h.ServerSwitches),
+		ExcludeDuration: helpers.CopyDurationPtr(h.ExcludeDuration),
+		TCPFallback:     helpers.CopyBoolPtr(h.TCPFallback),
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (h *HealthRecovery) mergeWith(other HealthRecovery) {
+	h.Restarts = helpers.MergeWithUint8(h.Restarts, other.Restarts)
+	h.ServerSwitches = helpers.MergeWithUint8(h.ServerSwitches, other.ServerSwitches)
+	h.ExcludeDuration = helpers.MergeWithDuration(h.ExcludeDuration, other.ExcludeDuration)
+	h.TCPFallback = helpers.MergeWithBool(h.TCPFallback, other.TCPFallback)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (h *HealthRecovery) overrideWith(other HealthRecovery) {
+	h.Restarts = helpers.OverrideWithUint8(h.Restarts, other.Restarts)
+	h.ServerSwitches = helpers.OverrideWithUint8(h.ServerSwitches, other.ServerSwitches)
+	h.ExcludeDuration = helpers.OverrideWithDuration(h.ExcludeDuration, other.ExcludeDuration)
+	h.TCPFallback = helpers.OverrideWithBool(h.TCPFallback, other.TCPFallback)
+}
+
+func (h *HealthRecovery) setDefaults() {
+	const (
+		defaultRestarts        = 2
+		defaultServerSwitches  = 2
+		defaultExcludeDuration = 10 * time.Minute
+	)
+	h.Restarts = helpers.DefaultUint8(h.Restarts, defaultRestarts)
+	h.ServerSwitches = helpers.DefaultUint8(h.ServerSwitches, defaultServerSwitches)
+	h.ExcludeDuration = helpers.DefaultDuration(h.ExcludeDuration, defaultExcludeDuration)
+	h.TCPFallback = helpers.DefaultBool(h.TCPFallback, false)
+}
+
+func (h HealthRecovery) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h HealthRecovery) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Recovery:")
+	node.Appendf("Restarts before switching server: %d", *h.Restarts)
+	node.Appendf("Server switches: %d", *h.ServerSwitches)
+	node.Appendf("Server exclusion duration: %s", *h.ExcludeDuration)
+	node.Appendf("Fall back to TCP: %s", helpers.BoolPtrToYesNo(h.TCPFallback))
+	return node
+}
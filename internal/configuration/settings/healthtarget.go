@@ -0,0 +1,75 @@
+package settings
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// HealthTarget is a target to probe for the health check.
+type HealthTarget struct {
+	// Type is the probe type and can be 'tcp', 'http',
+	// 'dns' or 'icmp'.
+	Type string
+	// Address is the address to probe, which is a host
+	// or host:port address for 'tcp', a URL for 'http',
+	// a hostname for 'dns' and a host for 'icmp'.
+	Address string
+	// ExpectedStatus is the HTTP response status code expected
+	// for the 'http' probe type. It is ignored for other types,
+	// and defaults to 200 for the 'http' type.
+	ExpectedStatus int
+}
+
+func (h HealthTarget) validate() (err error) {
+	if h.Address == "" {
+		return fmt.Errorf("%w: for %s probe", ErrHealthTargetAddressNotSet, h.Type)
+	}
+
+	switch h.Type {
+	case constants.HealthProbeTCP, constants.HealthProbeDNS, constants.HealthProbeICMP:
+	case constants.HealthProbeHTTP:
+		u, err := url.Parse(h.Address)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrHealthTargetURLNotValid, err)
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %s", ErrHealthTargetURLNotValid, h.Address)
+		}
+
+		const minStatus, maxStatus = 100, 599
+		if h.ExpectedStatus < minStatus || h.ExpectedStatus > maxStatus {
+			return fmt.Errorf("%w: %d must be between %d and %d",
+				ErrHealthTargetStatusNotValid, h.ExpectedStatus, minStatus, maxStatus)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrHealthTargetTypeNotValid, h.Type)
+	}
+
+	return nil
+}
+
+func (h *HealthTarget) setDefaults() {
+	if h.Type == constants.HealthProbeHTTP && h.ExpectedStatus == 0 {
+		h.ExpectedStatus = http.StatusOK
+	}
+}
+
+func (h HealthTarget) String() string {
+	s := h.Type + " " + h.Address
+	if h.Type == constants.HealthProbeHTTP {
+		s += " (expected status " + strconv.Itoa(h.ExpectedStatus) + ")"
+	}
+	return s
+}
+
+func copyHealthTargets(targets []HealthTarget) (copied []HealthTarget) {
+	if targets == nil {
+		return nil
+	}
+	copied = make([]HealthTarget, len(targets))
+	copy(copied, targets)
+	return copied
+}
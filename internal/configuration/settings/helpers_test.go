@@ -1,4 +1,8 @@
 package settings
 
-func boolPtr(b bool) *bool    { return &b }
-func uint8Ptr(n uint8) *uint8 { return &n }
+import "time"
+
+func boolPtr(b bool) *bool                       { return &b }
+func uint8Ptr(n uint8) *uint8                    { return &n }
+func durationPtr(d time.Duration) *time.Duration { return &d }
+func stringPtr(s string) *string                 { return &s }
@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// Hooks contains settings for user commands to run
+// when events happen, such as the VPN tunnel going up.
+type Hooks struct {
+	// TunnelUp is the command to run when the VPN tunnel is up.
+	// It can be the empty string to run no command, and cannot be
+	// nil in the internal state.
+	TunnelUp *string
+	// TunnelDown is the command to run when the VPN connection
+	// is stopped. It can be the empty string to run no command,
+	// and cannot be nil in the internal state.
+	TunnelDown *string
+	// PortForwarded is the command to run when the forwarded
+	// port changes. It can be the empty string to run no command,
+	// and cannot be nil in the internal state.
+	PortForwarded *string
+	// PublicIPChanged is the command to run when the public IP
+	// address changes. It can be the empty string to run no
+	// command, and cannot be nil in the internal state.
+	PublicIPChanged *string
+	// Timeout is the maximum duration a command can run for,
+	// before being killed. It cannot be nil in the internal state.
+	Timeout *time.Duration
+}
+
+func (h Hooks) validate() (err error) {
+	const minTimeout = time.Second
+	if *h.Timeout < minTimeout {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrHookTimeoutTooSmall, *h.Timeout, minTimeout)
+	}
+
+	return nil
+}
+
+func (h *Hooks) copy() (copied Hooks) {
+	return Hooks{
+		TunnelUp:        helpers.CopyStringPtr(h.TunnelUp),
+		TunnelDown:      helpers.CopyStringPtr(h.TunnelDown),
+		PortForwarded:   helpers.CopyStringPtr(h.PortForwarded),
+		PublicIPChanged: helpers.CopyStringPtr(h.PublicIPChanged),
+		Timeout:         helpers.CopyDurationPtr(h.Timeout),
+	}
+}
+
+func (h *Hooks) mergeWith(other Hooks) {
+	h.TunnelUp = helpers.MergeWithStringPtr(h.TunnelUp, other.TunnelUp)
+	h.TunnelDown = helpers.MergeWithStringPtr(h.TunnelDown, other.TunnelDown)
+	h.PortForwarded = helpers.MergeWithStringPtr(h.PortForwarded, other.PortForwarded)
+	h.PublicIPChanged = helpers.MergeWithStringPtr(h.PublicIPChanged, other.PublicIPChanged)
+	h.Timeout = helpers.MergeWithDuration(h.Timeout, other.Timeout)
+}
+
+func (h *Hooks) overrideWith(other Hooks) {
+	h.TunnelUp = helpers.OverrideWithStringPtr(h.TunnelUp, other.TunnelUp)
+	h.TunnelDown = helpers.OverrideWithStringPtr(h.TunnelDown, other.TunnelDown)
+	h.PortForwarded = helpers.OverrideWithStringPtr(h.PortForwarded, other.PortForwarded)
+	h.PublicIPChanged = helpers.OverrideWithStringPtr(h.PublicIPChanged, other.PublicIPChanged)
+	h.Timeout = helpers.OverrideWithDuration(h.Timeout, other.Timeout)
+}
+
+func (h *Hooks) setDefaults() {
+	h.TunnelUp = helpers.DefaultStringPtr(h.TunnelUp, "")
+	h.TunnelDown = helpers.DefaultStringPtr(h.TunnelDown, "")
+	h.PortForwarded = helpers.DefaultStringPtr(h.PortForwarded, "")
+	h.PublicIPChanged = helpers.DefaultStringPtr(h.PublicIPChanged, "")
+	const defaultTimeout = 30 * time.Second
+	h.Timeout = helpers.DefaultDuration(h.Timeout, defaultTimeout)
+}
+
+func (h Hooks) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h Hooks) toLinesNode() (node *gotree.Node) {
+	if *h.TunnelUp == "" && *h.TunnelDown == "" &&
+		*h.PortForwarded == "" && *h.PublicIPChanged == "" {
+		return nil
+	}
+
+	node = gotree.New("Hooks settings:")
+	if *h.TunnelUp != "" {
+		node.Appendf("Tunnel up command: %s", *h.TunnelUp)
+	}
+	if *h.TunnelDown != "" {
+		node.Appendf("Tunnel down command: %s", *h.TunnelDown)
+	}
+	if *h.PortForwarded != "" {
+		node.Appendf("Port forwarded command: %s", *h.PortForwarded)
+	}
+	if *h.PublicIPChanged != "" {
+		node.Appendf("Public IP changed command: %s", *h.PublicIPChanged)
+	}
+	node.Appendf("Timeout: %s", *h.Timeout)
+
+	return node
+}
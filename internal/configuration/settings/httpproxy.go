@@ -81,6 +81,21 @@ func (h *HTTPProxy) overrideWith(other HTTPProxy) {
 	h.Log = helpers.OverrideWithBool(h.Log, other.Log)
 }
 
+// OverrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings, and validates the resulting settings.
+// The receiver is left unchanged if an error is returned.
+func (h *HTTPProxy) OverrideWith(other HTTPProxy) (err error) {
+	patchedSettings := h.copy()
+	patchedSettings.overrideWith(other)
+	err = patchedSettings.validate()
+	if err != nil {
+		return err
+	}
+	*h = patchedSettings
+	return nil
+}
+
 func (h *HTTPProxy) setDefaults() {
 	h.User = helpers.DefaultStringPtr(h.User, "")
 	h.Password = helpers.DefaultStringPtr(h.Password, "")
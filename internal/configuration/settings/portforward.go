@@ -20,6 +20,12 @@ type PortForwarding struct {
 	// to write to a file. It cannot be nil for the
 	// internal state
 	Filepath *string
+	// QBittorrent contains settings to set the forwarded
+	// port as the listening port of a qBittorrent client.
+	QBittorrent PortForwardingClient
+	// Transmission contains settings to set the forwarded
+	// port as the listening port of a Transmission client.
+	Transmission PortForwardingClient
 }
 
 func (p PortForwarding) validate(vpnProvider string) (err error) {
@@ -28,7 +34,7 @@ func (p PortForwarding) validate(vpnProvider string) (err error) {
 	}
 
 	// Validate Enabled
-	validProviders := []string{providers.PrivateInternetAccess}
+	validProviders := []string{providers.PrivateInternetAccess, providers.Protonvpn}
 	if !helpers.IsOneOf(vpnProvider, validProviders...) {
 		return fmt.Errorf("%w: for provider %s, it is only available for %s",
 			ErrPortForwardingEnabled, vpnProvider, strings.Join(validProviders, ", "))
@@ -42,29 +48,47 @@ func (p PortForwarding) validate(vpnProvider string) (err error) {
 		}
 	}
 
+	err = p.QBittorrent.validate()
+	if err != nil {
+		return fmt.Errorf("qBittorrent client: %w", err)
+	}
+
+	err = p.Transmission.validate()
+	if err != nil {
+		return fmt.Errorf("Transmission client: %w", err)
+	}
+
 	return nil
 }
 
 func (p *PortForwarding) copy() (copied PortForwarding) {
 	return PortForwarding{
-		Enabled:  helpers.CopyBoolPtr(p.Enabled),
-		Filepath: helpers.CopyStringPtr(p.Filepath),
+		Enabled:      helpers.CopyBoolPtr(p.Enabled),
+		Filepath:     helpers.CopyStringPtr(p.Filepath),
+		QBittorrent:  p.QBittorrent.copy(),
+		Transmission: p.Transmission.copy(),
 	}
 }
 
 func (p *PortForwarding) mergeWith(other PortForwarding) {
 	p.Enabled = helpers.MergeWithBool(p.Enabled, other.Enabled)
 	p.Filepath = helpers.MergeWithStringPtr(p.Filepath, other.Filepath)
+	p.QBittorrent.mergeWith(other.QBittorrent)
+	p.Transmission.mergeWith(other.Transmission)
 }
 
 func (p *PortForwarding) overrideWith(other PortForwarding) {
 	p.Enabled = helpers.OverrideWithBool(p.Enabled, other.Enabled)
 	p.Filepath = helpers.OverrideWithStringPtr(p.Filepath, other.Filepath)
+	p.QBittorrent.overrideWith(other.QBittorrent)
+	p.Transmission.overrideWith(other.Transmission)
 }
 
 func (p *PortForwarding) setDefaults() {
 	p.Enabled = helpers.DefaultBool(p.Enabled, false)
 	p.Filepath = helpers.DefaultStringPtr(p.Filepath, "/tmp/gluetun/forwarded_port")
+	p.QBittorrent.setDefaults()
+	p.Transmission.setDefaults()
 }
 
 func (p PortForwarding) String() string {
@@ -84,6 +108,8 @@ func (p PortForwarding) toLinesNode() (node *gotree.Node) {
 		filepath = "[not set]"
 	}
 	node.Appendf("Forwarded port file path: %s", filepath)
+	node.AppendNode(p.QBittorrent.toLinesNode("qBittorrent"))
+	node.AppendNode(p.Transmission.toLinesNode("Transmission"))
 
 	return node
 }
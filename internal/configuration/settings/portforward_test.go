@@ -17,3 +17,33 @@ func Test_PortForwarding_String(t *testing.T) {
 
 	assert.Empty(t, s)
 }
+
+func Test_PortForwarding_String_clients(t *testing.T) {
+	t.Parallel()
+
+	settings := PortForwarding{
+		Enabled:  boolPtr(true),
+		Filepath: stringPtr(""),
+		QBittorrent: PortForwardingClient{
+			Address:  stringPtr("http://localhost:8080"),
+			User:     stringPtr("admin"),
+			Password: stringPtr("password"),
+		},
+		Transmission: PortForwardingClient{
+			Address:  stringPtr(""),
+			User:     stringPtr(""),
+			Password: stringPtr(""),
+		},
+	}
+
+	s := settings.String()
+
+	const expected = `Automatic port forwarding settings:
+├── Enabled: yes
+├── Forwarded port file path: [not set]
+└── qBittorrent client:
+    ├── Address: http://localhost:8080
+    ├── User: admin
+    └── Password: [set]`
+	assert.Equal(t, expected, s)
+}
@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// PortForwardingClient contains settings to set the forwarded
+// port as the listening port of a torrent client.
+type PortForwardingClient struct {
+	// Address is the base URL of the client web API,
+	// for example http://localhost:8080. It can be the
+	// empty string to disable setting the port on the client,
+	// and cannot be nil in the internal state.
+	Address *string
+	// User is the user to authenticate with the client.
+	// It can be the empty string if no authentication is
+	// required, and cannot be nil in the internal state.
+	User *string
+	// Password is the password to authenticate with the client.
+	// It cannot be nil in the internal state.
+	Password *string
+}
+
+func (p PortForwardingClient) validate() (err error) {
+	if *p.Address == "" {
+		return nil
+	}
+
+	address, err := url.Parse(*p.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPortForwardClientURLNotValid, err)
+	}
+
+	switch address.Scheme {
+	case "http", "https":
+	default:
+		return fmt.Errorf("%w: scheme %q is not http or https",
+			ErrPortForwardClientURLNotValid, address.Scheme)
+	}
+
+	if address.Host == "" {
+		return fmt.Errorf("%w: host is missing", ErrPortForwardClientURLNotValid)
+	}
+
+	return nil
+}
+
+func (p *PortForwardingClient) copy() (copied PortForwardingClient) {
+	return PortForwardingClient{
+		Address:  helpers.CopyStringPtr(p.Address),
+		User:     helpers.CopyStringPtr(p.User),
+		Password: helpers.CopyStringPtr(p.Password),
+	}
+}
+
+func (p *PortForwardingClient) mergeWith(other PortForwardingClient) {
+	p.Address = helpers.MergeWithStringPtr(p.Address, other.Address)
+	p.User = helpers.MergeWithStringPtr(p.User, other.User)
+	p.Password = helpers.MergeWithStringPtr(p.Password, other.Password)
+}
+
+func (p *PortForwardingClient) overrideWith(other PortForwardingClient) {
+	p.Address = helpers.OverrideWithStringPtr(p.Address, other.Address)
+	p.User = helpers.OverrideWithStringPtr(p.User, other.User)
+	p.Password = helpers.OverrideWithStringPtr(p.Password, other.Password)
+}
+
+func (p *PortForwardingClient) setDefaults() {
+	p.Address = helpers.DefaultStringPtr(p.Address, "")
+	p.User = helpers.DefaultStringPtr(p.User, "")
+	p.Password = helpers.DefaultStringPtr(p.Password, "")
+}
+
+func (p PortForwardingClient) toLinesNode(name string) (node *gotree.Node) {
+	if *p.Address == "" {
+		return nil
+	}
+
+	node = gotree.New("%s client:", name)
+	node.Appendf("Address: %s", *p.Address)
+	if *p.User != "" {
+		node.Appendf("User: %s", *p.User)
+		node.Appendf("Password: %s", helpers.ObfuscatePassword(*p.Password))
+	}
+
+	return node
+}
@@ -4,24 +4,59 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/gotree"
 )
 
 // ControlServer contains settings to customize the control server operation.
 type ControlServer struct {
 	// Address is the listening address to use.
+	// It can be a Unix domain socket address such
+	// as unix:/gluetun/control.sock.
 	// It cannot be nil in the internal state.
 	Address *string
 	// Log can be true or false to enable logging on requests.
 	// It cannot be nil in the internal state.
 	Log *bool
+	// Auth contains the authentication settings
+	// for the control server.
+	Auth ControlServerAuth
+	// TLS contains the settings to serve the control
+	// server over HTTPS. TLS is disabled by default.
+	TLS httpserver.TLS
 }
 
 func (c ControlServer) validate() (err error) {
-	_, portStr, err := net.SplitHostPort(*c.Address)
+	if path, ok := httpserver.UnixSocketPath(*c.Address); ok {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("%w: %s", ErrControlServerUnixSocketPath, path)
+		}
+	} else {
+		err = validateControlServerTCPAddress(*c.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Auth.validate()
+	if err != nil {
+		return fmt.Errorf("authentication: %w", err)
+	}
+
+	err = c.TLS.Validate()
+	if err != nil {
+		return fmt.Errorf("TLS: %w", err)
+	}
+
+	return nil
+}
+
+func validateControlServerTCPAddress(address string) (err error) {
+	_, portStr, err := net.SplitHostPort(address)
 	if err != nil {
 		return fmt.Errorf("listening address is not valid: %w", err)
 	}
@@ -45,6 +80,8 @@ func (c *ControlServer) copy() (copied ControlServer) {
 	return ControlServer{
 		Address: helpers.CopyStringPtr(c.Address),
 		Log:     helpers.CopyBoolPtr(c.Log),
+		Auth:    c.Auth.copy(),
+		TLS:     c.TLS.Copy(),
 	}
 }
 
@@ -53,6 +90,8 @@ func (c *ControlServer) copy() (copied ControlServer) {
 func (c *ControlServer) mergeWith(other ControlServer) {
 	c.Address = helpers.MergeWithStringPtr(c.Address, other.Address)
 	c.Log = helpers.MergeWithBool(c.Log, other.Log)
+	c.Auth.mergeWith(other.Auth)
+	c.TLS.MergeWith(other.TLS)
 }
 
 // overrideWith overrides fields of the receiver
@@ -61,11 +100,14 @@ func (c *ControlServer) mergeWith(other ControlServer) {
 func (c *ControlServer) overrideWith(other ControlServer) {
 	c.Address = helpers.OverrideWithStringPtr(c.Address, other.Address)
 	c.Log = helpers.OverrideWithBool(c.Log, other.Log)
+	c.Auth.overrideWith(other.Auth)
+	c.TLS.OverrideWith(other.TLS)
 }
 
 func (c *ControlServer) setDefaults() {
 	c.Address = helpers.DefaultStringPtr(c.Address, ":8000")
 	c.Log = helpers.DefaultBool(c.Log, true)
+	c.Auth.setDefaults()
 }
 
 func (c ControlServer) String() string {
@@ -76,5 +118,9 @@ func (c ControlServer) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Control server settings:")
 	node.Appendf("Listening address: %s", *c.Address)
 	node.Appendf("Logging: %s", helpers.BoolPtrToYesNo(c.Log))
+	node.AppendNode(c.Auth.toLinesNode())
+	if c.TLS.Enabled() {
+		node.AppendNode(c.TLS.ToLinesNode())
+	}
 	return node
 }
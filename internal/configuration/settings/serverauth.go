@@ -0,0 +1,161 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// ControlServerAuth contains settings to authenticate and
+// authorize requests to the control server.
+// If no credential is set for any role, authentication is disabled.
+type ControlServerAuth struct {
+	// Admin contains the credentials for the admin role,
+	// which is allowed to use all the routes.
+	Admin ControlServerRole
+	// ReadOnly contains the credentials for the read only role,
+	// which is only allowed to use GET routes.
+	ReadOnly ControlServerRole
+}
+
+func (c ControlServerAuth) validate() (err error) {
+	err = c.Admin.validate()
+	if err != nil {
+		return fmt.Errorf("admin role: %w", err)
+	}
+
+	err = c.ReadOnly.validate()
+	if err != nil {
+		return fmt.Errorf("read only role: %w", err)
+	}
+
+	if *c.Admin.APIKey != "" && *c.Admin.APIKey == *c.ReadOnly.APIKey {
+		return fmt.Errorf("%w", ErrControlServerAPIKeyDuplicate)
+	}
+
+	if *c.Admin.User != "" && *c.Admin.User == *c.ReadOnly.User {
+		return fmt.Errorf("%w: %s", ErrControlServerUserDuplicate, *c.Admin.User)
+	}
+
+	return nil
+}
+
+func (c *ControlServerAuth) copy() (copied ControlServerAuth) {
+	return ControlServerAuth{
+		Admin:    c.Admin.copy(),
+		ReadOnly: c.ReadOnly.copy(),
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (c *ControlServerAuth) mergeWith(other ControlServerAuth) {
+	c.Admin.mergeWith(other.Admin)
+	c.ReadOnly.mergeWith(other.ReadOnly)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (c *ControlServerAuth) overrideWith(other ControlServerAuth) {
+	c.Admin.overrideWith(other.Admin)
+	c.ReadOnly.overrideWith(other.ReadOnly)
+}
+
+func (c *ControlServerAuth) setDefaults() {
+	c.Admin.setDefaults()
+	c.ReadOnly.setDefaults()
+}
+
+// Enabled returns true if at least one credential
+// is set for one of the roles.
+func (c ControlServerAuth) Enabled() bool {
+	return c.Admin.isSet() || c.ReadOnly.isSet()
+}
+
+func (c ControlServerAuth) String() string {
+	return c.toLinesNode().String()
+}
+
+func (c ControlServerAuth) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Authentication:")
+	if !c.Enabled() {
+		node.Appendf("Enabled: no")
+		return node
+	}
+
+	node.AppendNode(c.Admin.toLinesNode("Admin"))
+	node.AppendNode(c.ReadOnly.toLinesNode("Read only"))
+	return node
+}
+
+// ControlServerRole contains the credentials to authenticate
+// a role on the control server.
+type ControlServerRole struct {
+	// APIKey is the API key to send in the X-API-Key header.
+	// It cannot be nil in the internal state, and is
+	// disabled if it is the empty string.
+	APIKey *string
+	// User is the username for HTTP basic authentication.
+	// It cannot be nil in the internal state, and basic
+	// authentication is disabled if it is the empty string.
+	User *string
+	// Password is the password for HTTP basic authentication.
+	// It cannot be nil in the internal state.
+	Password *string
+}
+
+func (c ControlServerRole) validate() (err error) {
+	if *c.User != "" && *c.Password == "" {
+		return fmt.Errorf("%w: for user %s", ErrControlServerPasswordMissing, *c.User)
+	} else if *c.User == "" && *c.Password != "" {
+		return fmt.Errorf("%w", ErrControlServerUserMissing)
+	}
+	return nil
+}
+
+func (c *ControlServerRole) copy() (copied ControlServerRole) {
+	return ControlServerRole{
+		APIKey:   helpers.CopyStringPtr(c.APIKey),
+		User:     helpers.CopyStringPtr(c.User),
+		Password: helpers.CopyStringPtr(c.Password),
+	}
+}
+
+// mergeWith merges the other settings into any
+// unset field of the receiver settings object.
+func (c *ControlServerRole) mergeWith(other ControlServerRole) {
+	c.APIKey = helpers.MergeWithStringPtr(c.APIKey, other.APIKey)
+	c.User = helpers.MergeWithStringPtr(c.User, other.User)
+	c.Password = helpers.MergeWithStringPtr(c.Password, other.Password)
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (c *ControlServerRole) overrideWith(other ControlServerRole) {
+	c.APIKey = helpers.OverrideWithStringPtr(c.APIKey, other.APIKey)
+	c.User = helpers.OverrideWithStringPtr(c.User, other.User)
+	c.Password = helpers.OverrideWithStringPtr(c.Password, other.Password)
+}
+
+func (c *ControlServerRole) setDefaults() {
+	c.APIKey = helpers.DefaultStringPtr(c.APIKey, "")
+	c.User = helpers.DefaultStringPtr(c.User, "")
+	c.Password = helpers.DefaultStringPtr(c.Password, "")
+}
+
+func (c ControlServerRole) isSet() bool {
+	return *c.APIKey != "" || *c.User != ""
+}
+
+func (c ControlServerRole) toLinesNode(role string) (node *gotree.Node) {
+	node = gotree.New(role + " role:")
+	node.Appendf("API key: %s", helpers.ObfuscatePassword(*c.APIKey))
+	if *c.User != "" {
+		node.Appendf("User: %s", *c.User)
+		node.Appendf("Password: %s", helpers.ObfuscatePassword(*c.Password))
+	}
+	return node
+}
@@ -51,6 +51,14 @@ type ServerSelection struct { //nolint:maligned
 	// MultiHopOnly is true if VPN servers that are not multihop
 	// should be filtered. This is used with Surfshark.
 	MultiHopOnly *bool
+	// PortForwardOnly is true if VPN servers not supporting
+	// port forwarding should be filtered. This is used with ProtonVPN.
+	PortForwardOnly *bool
+	// Strategy is the strategy to pick a connection from the
+	// filtered servers, and can be 'random', 'lowest-latency'
+	// or 'weighted'. It cannot be the empty string in the
+	// internal state.
+	Strategy string
 
 	// OpenVPN contains settings to select OpenVPN servers
 	// and the final connection.
@@ -61,10 +69,12 @@ type ServerSelection struct { //nolint:maligned
 }
 
 var (
-	ErrOwnedOnlyNotSupported    = errors.New("owned only filter is not supported")
-	ErrFreeOnlyNotSupported     = errors.New("free only filter is not supported")
-	ErrStreamOnlyNotSupported   = errors.New("stream only filter is not supported")
-	ErrMultiHopOnlyNotSupported = errors.New("multi hop only filter is not supported")
+	ErrOwnedOnlyNotSupported       = errors.New("owned only filter is not supported")
+	ErrFreeOnlyNotSupported        = errors.New("free only filter is not supported")
+	ErrStreamOnlyNotSupported      = errors.New("stream only filter is not supported")
+	ErrMultiHopOnlyNotSupported    = errors.New("multi hop only filter is not supported")
+	ErrPortForwardOnlyNotSupported = errors.New("port forward only filter is not supported")
+	ErrPortForwardOnlyNoServer     = errors.New("no server is known to support port forwarding")
 )
 
 func (ss *ServerSelection) validate(vpnServiceProvider string,
@@ -120,6 +130,27 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 			ErrMultiHopOnlyNotSupported, vpnServiceProvider)
 	}
 
+	if *ss.PortForwardOnly &&
+		vpnServiceProvider != providers.Protonvpn {
+		return fmt.Errorf("%w: for VPN service provider %s",
+			ErrPortForwardOnlyNotSupported, vpnServiceProvider)
+	}
+
+	if *ss.PortForwardOnly && !hasPortForwardServer(vpnServiceProvider, allServers) {
+		// Servers data from before port forwarding support was
+		// recorded have no server marked as supporting it.
+		return fmt.Errorf("%w: for VPN service provider %s, "+
+			"try updating the servers data",
+			ErrPortForwardOnlyNoServer, vpnServiceProvider)
+	}
+
+	switch ss.Strategy {
+	case constants.SelectionRandom, constants.SelectionLowestLatency,
+		constants.SelectionWeighted:
+	default:
+		return fmt.Errorf("%w: %s", ErrSelectionStrategyNotValid, ss.Strategy)
+	}
+
 	if ss.VPN == constants.OpenVPN {
 		err = ss.OpenVPN.validate(vpnServiceProvider)
 		if err != nil {
@@ -135,6 +166,20 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 	return nil
 }
 
+func hasPortForwardServer(vpnServiceProvider string,
+	allServers models.AllServers) bool {
+	providerServers := allServers.ServersByProvider(vpnServiceProvider)
+	if providerServers == nil {
+		return false
+	}
+	for _, server := range providerServers.Servers {
+		if server.PortForward {
+			return true
+		}
+	}
+	return false
+}
+
 func getLocationFilterChoices(vpnServiceProvider string, ss *ServerSelection,
 	allServers models.AllServers) (
 	countryChoices, regionChoices, cityChoices,
@@ -292,21 +337,23 @@ func validateServerFilters(settings ServerSelection,
 
 func (ss *ServerSelection) copy() (copied ServerSelection) {
 	return ServerSelection{
-		VPN:          ss.VPN,
-		TargetIP:     helpers.CopyIP(ss.TargetIP),
-		Countries:    helpers.CopyStringSlice(ss.Countries),
-		Regions:      helpers.CopyStringSlice(ss.Regions),
-		Cities:       helpers.CopyStringSlice(ss.Cities),
-		ISPs:         helpers.CopyStringSlice(ss.ISPs),
-		Hostnames:    helpers.CopyStringSlice(ss.Hostnames),
-		Names:        helpers.CopyStringSlice(ss.Names),
-		Numbers:      helpers.CopyUint16Slice(ss.Numbers),
-		OwnedOnly:    helpers.CopyBoolPtr(ss.OwnedOnly),
-		FreeOnly:     helpers.CopyBoolPtr(ss.FreeOnly),
-		StreamOnly:   helpers.CopyBoolPtr(ss.StreamOnly),
-		MultiHopOnly: helpers.CopyBoolPtr(ss.MultiHopOnly),
-		OpenVPN:      ss.OpenVPN.copy(),
-		Wireguard:    ss.Wireguard.copy(),
+		VPN:             ss.VPN,
+		TargetIP:        helpers.CopyIP(ss.TargetIP),
+		Countries:       helpers.CopyStringSlice(ss.Countries),
+		Regions:         helpers.CopyStringSlice(ss.Regions),
+		Cities:          helpers.CopyStringSlice(ss.Cities),
+		ISPs:            helpers.CopyStringSlice(ss.ISPs),
+		Hostnames:       helpers.CopyStringSlice(ss.Hostnames),
+		Names:           helpers.CopyStringSlice(ss.Names),
+		Numbers:         helpers.CopyUint16Slice(ss.Numbers),
+		OwnedOnly:       helpers.CopyBoolPtr(ss.OwnedOnly),
+		FreeOnly:        helpers.CopyBoolPtr(ss.FreeOnly),
+		StreamOnly:      helpers.CopyBoolPtr(ss.StreamOnly),
+		MultiHopOnly:    helpers.CopyBoolPtr(ss.MultiHopOnly),
+		PortForwardOnly: helpers.CopyBoolPtr(ss.PortForwardOnly),
+		Strategy:        ss.Strategy,
+		OpenVPN:         ss.OpenVPN.copy(),
+		Wireguard:       ss.Wireguard.copy(),
 	}
 }
 
@@ -324,6 +371,8 @@ func (ss *ServerSelection) mergeWith(other ServerSelection) {
 	ss.FreeOnly = helpers.MergeWithBool(ss.FreeOnly, other.FreeOnly)
 	ss.StreamOnly = helpers.MergeWithBool(ss.StreamOnly, other.StreamOnly)
 	ss.MultiHopOnly = helpers.MergeWithBool(ss.MultiHopOnly, other.MultiHopOnly)
+	ss.PortForwardOnly = helpers.MergeWithBool(ss.PortForwardOnly, other.PortForwardOnly)
+	ss.Strategy = helpers.MergeWithString(ss.Strategy, other.Strategy)
 
 	ss.OpenVPN.mergeWith(other.OpenVPN)
 	ss.Wireguard.mergeWith(other.Wireguard)
@@ -343,6 +392,8 @@ func (ss *ServerSelection) overrideWith(other ServerSelection) {
 	ss.FreeOnly = helpers.OverrideWithBool(ss.FreeOnly, other.FreeOnly)
 	ss.StreamOnly = helpers.OverrideWithBool(ss.StreamOnly, other.StreamOnly)
 	ss.MultiHopOnly = helpers.OverrideWithBool(ss.MultiHopOnly, other.MultiHopOnly)
+	ss.PortForwardOnly = helpers.OverrideWithBool(ss.PortForwardOnly, other.PortForwardOnly)
+	ss.Strategy = helpers.OverrideWithString(ss.Strategy, other.Strategy)
 	ss.OpenVPN.overrideWith(other.OpenVPN)
 	ss.Wireguard.overrideWith(other.Wireguard)
 }
@@ -354,6 +405,8 @@ func (ss *ServerSelection) setDefaults(vpnProvider string) {
 	ss.FreeOnly = helpers.DefaultBool(ss.FreeOnly, false)
 	ss.StreamOnly = helpers.DefaultBool(ss.StreamOnly, false)
 	ss.MultiHopOnly = helpers.DefaultBool(ss.MultiHopOnly, false)
+	ss.PortForwardOnly = helpers.DefaultBool(ss.PortForwardOnly, false)
+	ss.Strategy = helpers.DefaultString(ss.Strategy, constants.SelectionRandom)
 	ss.OpenVPN.setDefaults(vpnProvider)
 	ss.Wireguard.setDefaults()
 }
@@ -416,6 +469,14 @@ func (ss ServerSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Multi-hop only servers: yes")
 	}
 
+	if *ss.PortForwardOnly {
+		node.Appendf("Port forwarding only servers: yes")
+	}
+
+	if ss.Strategy != constants.SelectionRandom {
+		node.Appendf("Selection strategy: %s", ss.Strategy)
+	}
+
 	if ss.VPN == constants.OpenVPN {
 		node.AppendNode(ss.OpenVPN.toLinesNode())
 	} else {
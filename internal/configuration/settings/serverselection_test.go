@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ServerSelection_validate_portForwardOnly(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers    []models.Server
+		errWrapped error
+		errMessage string
+	}{
+		"server supporting port forwarding": {
+			servers: []models.Server{
+				{VPN: "openvpn", Hostname: "a.com", UDP: true},
+				{VPN: "openvpn", Hostname: "b.com", UDP: true, PortForward: true},
+			},
+		},
+		"no server supporting port forwarding": {
+			servers: []models.Server{
+				{VPN: "openvpn", Hostname: "a.com", UDP: true},
+			},
+			errWrapped: ErrPortForwardOnlyNoServer,
+			errMessage: "no server is known to support port forwarding: " +
+				"for VPN service provider protonvpn, try updating the servers data",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			selection := ServerSelection{
+				PortForwardOnly: boolPtr(true),
+			}.WithDefaults(providers.Protonvpn)
+			allServers := models.AllServers{
+				Protonvpn: models.Servers{Servers: testCase.servers},
+			}
+
+			err := selection.validate(providers.Protonvpn, allServers)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
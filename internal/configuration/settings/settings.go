@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/metrics"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/pprof"
 	"github.com/qdm12/gotree"
@@ -13,6 +14,7 @@ type Settings struct {
 	DNS           DNS
 	Firewall      Firewall
 	Health        Health
+	Hooks         Hooks
 	HTTPProxy     HTTPProxy
 	Log           Log
 	PublicIP      PublicIP
@@ -22,6 +24,7 @@ type Settings struct {
 	Version       Version
 	VPN           VPN
 	Pprof         pprof.Settings
+	Metrics       metrics.Settings
 }
 
 // Validate validates all the settings and returns an error
@@ -33,6 +36,7 @@ func (s *Settings) Validate(allServers models.AllServers) (err error) {
 		"dns":             s.DNS.validate,
 		"firewall":        s.Firewall.validate,
 		"health":          s.Health.Validate,
+		"hooks":           s.Hooks.validate,
 		"http proxy":      s.HTTPProxy.validate,
 		"log":             s.Log.validate,
 		"public ip check": s.PublicIP.validate,
@@ -41,6 +45,7 @@ func (s *Settings) Validate(allServers models.AllServers) (err error) {
 		"updater":         s.Updater.Validate,
 		"version":         s.Version.validate,
 		// Pprof validation done in pprof constructor
+		// Metrics validation done in metrics server constructor
 		"VPN": func() error {
 			return s.VPN.validate(allServers)
 		},
@@ -62,6 +67,7 @@ func (s *Settings) copy() (copied Settings) {
 		DNS:           s.DNS.Copy(),
 		Firewall:      s.Firewall.copy(),
 		Health:        s.Health.copy(),
+		Hooks:         s.Hooks.copy(),
 		HTTPProxy:     s.HTTPProxy.copy(),
 		Log:           s.Log.copy(),
 		PublicIP:      s.PublicIP.copy(),
@@ -71,6 +77,7 @@ func (s *Settings) copy() (copied Settings) {
 		Version:       s.Version.copy(),
 		VPN:           s.VPN.copy(),
 		Pprof:         s.Pprof.Copy(),
+		Metrics:       s.Metrics.Copy(),
 	}
 }
 
@@ -79,6 +86,7 @@ func (s *Settings) MergeWith(other Settings) {
 	s.DNS.mergeWith(other.DNS)
 	s.Firewall.mergeWith(other.Firewall)
 	s.Health.MergeWith(other.Health)
+	s.Hooks.mergeWith(other.Hooks)
 	s.HTTPProxy.mergeWith(other.HTTPProxy)
 	s.Log.mergeWith(other.Log)
 	s.PublicIP.mergeWith(other.PublicIP)
@@ -88,6 +96,7 @@ func (s *Settings) MergeWith(other Settings) {
 	s.Version.mergeWith(other.Version)
 	s.VPN.mergeWith(other.VPN)
 	s.Pprof.MergeWith(other.Pprof)
+	s.Metrics.MergeWith(other.Metrics)
 }
 
 func (s *Settings) OverrideWith(other Settings,
@@ -97,6 +106,7 @@ func (s *Settings) OverrideWith(other Settings,
 	patchedSettings.DNS.overrideWith(other.DNS)
 	patchedSettings.Firewall.overrideWith(other.Firewall)
 	patchedSettings.Health.OverrideWith(other.Health)
+	patchedSettings.Hooks.overrideWith(other.Hooks)
 	patchedSettings.HTTPProxy.overrideWith(other.HTTPProxy)
 	patchedSettings.Log.overrideWith(other.Log)
 	patchedSettings.PublicIP.overrideWith(other.PublicIP)
@@ -106,6 +116,7 @@ func (s *Settings) OverrideWith(other Settings,
 	patchedSettings.Version.overrideWith(other.Version)
 	patchedSettings.VPN.overrideWith(other.VPN)
 	patchedSettings.Pprof.MergeWith(other.Pprof)
+	patchedSettings.Metrics.OverrideWith(other.Metrics)
 	err = patchedSettings.Validate(allServers)
 	if err != nil {
 		return err
@@ -119,6 +130,7 @@ func (s *Settings) SetDefaults() {
 	s.DNS.setDefaults()
 	s.Firewall.setDefaults()
 	s.Health.SetDefaults()
+	s.Hooks.setDefaults()
 	s.HTTPProxy.setDefaults()
 	s.Log.setDefaults()
 	s.PublicIP.setDefaults()
@@ -128,6 +140,7 @@ func (s *Settings) SetDefaults() {
 	s.VPN.setDefaults()
 	s.Updater.SetDefaults(*s.VPN.Provider.Name)
 	s.Pprof.SetDefaults()
+	s.Metrics.SetDefaults()
 }
 
 func (s Settings) String() string {
@@ -142,6 +155,7 @@ func (s Settings) toLinesNode() (node *gotree.Node) {
 	node.AppendNode(s.Firewall.toLinesNode())
 	node.AppendNode(s.Log.toLinesNode())
 	node.AppendNode(s.Health.toLinesNode())
+	node.AppendNode(s.Hooks.toLinesNode())
 	node.AppendNode(s.Shadowsocks.toLinesNode())
 	node.AppendNode(s.HTTPProxy.toLinesNode())
 	node.AppendNode(s.ControlServer.toLinesNode())
@@ -150,6 +164,7 @@ func (s Settings) toLinesNode() (node *gotree.Node) {
 	node.AppendNode(s.Updater.toLinesNode())
 	node.AppendNode(s.Version.toLinesNode())
 	node.AppendNode(s.Pprof.ToLinesNode())
+	node.AppendNode(s.Metrics.ToLinesNode())
 
 	return node
 }
@@ -29,15 +29,16 @@ func Test_Settings_String(t *testing.T) {
 |   |       └── OpenVPN server selection settings:
 |   |           ├── Protocol: UDP
 |   |           └── Private Internet Access encryption preset: strong
-|   └── OpenVPN settings:
-|       ├── OpenVPN version: 2.5
-|       ├── User: [not set]
-|       ├── Password: [not set]
-|       ├── Private Internet Access encryption preset: strong
-|       ├── Tunnel IPv6: no
-|       ├── Network interface: tun0
-|       ├── Run OpenVPN as: root
-|       └── Verbosity level: 1
+|   ├── OpenVPN settings:
+|   |   ├── OpenVPN version: 2.5
+|   |   ├── User: [not set]
+|   |   ├── Password: [not set]
+|   |   ├── Private Internet Access encryption preset: strong
+|   |   ├── Tunnel IPv6: no
+|   |   ├── Network interface: tun0
+|   |   ├── Run OpenVPN as: root
+|   |   └── Verbosity level: 1
+|   └── Failing servers blocklist cooldown: 15m0s
 ├── DNS settings:
 |   ├── DNS server address to use: 127.0.0.1
 |   ├── Keep existing nameserver(s): no
@@ -66,17 +67,27 @@ func Test_Settings_String(t *testing.T) {
 |   └── Log level: INFO
 ├── Health settings:
 |   ├── Server listening address: 127.0.0.1:9999
-|   ├── Target address: cloudflare.com:443
-|   └── VPN wait durations:
-|       ├── Initial duration: 6s
-|       └── Additional duration: 5s
+|   ├── Targets (all must pass):
+|   |   └── tcp cloudflare.com:443
+|   ├── IP leak check: disabled
+|   ├── Wireguard handshake timeout: 3m0s
+|   ├── VPN wait durations:
+|   |   ├── Initial duration: 6s
+|   |   └── Additional duration: 5s
+|   └── Recovery:
+|       ├── Restarts before switching server: 2
+|       ├── Server switches: 2
+|       ├── Server exclusion duration: 10m0s
+|       └── Fall back to TCP: no
 ├── Shadowsocks server settings:
 |   └── Enabled: no
 ├── HTTP proxy settings:
 |   └── Enabled: no
 ├── Control server settings:
 |   ├── Listening address: :8000
-|   └── Logging: yes
+|   ├── Logging: yes
+|   └── Authentication:
+|       └── Enabled: no
 ├── OS Alpine settings:
 |   ├── Process UID: 1000
 |   └── Process GID: 1000
@@ -41,6 +41,21 @@ func (s *Shadowsocks) overrideWith(other Shadowsocks) {
 	s.Settings.OverrideWith(other.Settings)
 }
 
+// OverrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings, and validates the resulting settings.
+// The receiver is left unchanged if an error is returned.
+func (s *Shadowsocks) OverrideWith(other Shadowsocks) (err error) {
+	patchedSettings := s.copy()
+	patchedSettings.overrideWith(other)
+	err = patchedSettings.validate()
+	if err != nil {
+		return err
+	}
+	*s = patchedSettings
+	return nil
+}
+
 func (s *Shadowsocks) setDefaults() {
 	s.Enabled = helpers.DefaultBool(s.Enabled, false)
 	s.Settings.SetDefaults()
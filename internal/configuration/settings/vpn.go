@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -18,10 +19,35 @@ type VPN struct {
 	Provider  Provider
 	OpenVPN   OpenVPN
 	Wireguard Wireguard
+	// RotationPeriod is the period after which the VPN
+	// reconnects to a different server matching the server
+	// selection. It is disabled if set to 0, and cannot be
+	// nil in the internal state.
+	RotationPeriod *time.Duration
+	// BlocklistCooldown is the duration a server failing
+	// to connect is excluded from the server selection.
+	// It is disabled if set to 0, and cannot be nil in the
+	// internal state.
+	BlocklistCooldown *time.Duration
+	// Failover contains settings to fail over to other
+	// VPN profiles if these settings keep failing.
+	Failover Failover
 }
 
 // TODO v4 remove pointer for receiver (because of Surfshark).
 func (v *VPN) validate(allServers models.AllServers) (err error) {
+	err = v.validateConnection(allServers)
+	if err != nil {
+		return err
+	}
+
+	return v.validateProfiles(allServers)
+}
+
+// validateConnection validates the settings used to connect
+// to the VPN, which can be replaced by a failover profile.
+// TODO v4 remove pointer for receiver (because of Surfshark).
+func (v *VPN) validateConnection(allServers models.AllServers) (err error) {
 	// Validate Type
 	validVPNTypes := []string{constants.OpenVPN, constants.Wireguard}
 	if !helpers.IsOneOf(v.Type, validVPNTypes...) {
@@ -29,6 +55,12 @@ func (v *VPN) validate(allServers models.AllServers) (err error) {
 			ErrVPNTypeNotValid, v.Type, strings.Join(validVPNTypes, ", "))
 	}
 
+	const minRotationPeriod = time.Minute
+	if *v.RotationPeriod > 0 && *v.RotationPeriod < minRotationPeriod {
+		return fmt.Errorf("%w: %s must be larger than %s",
+			ErrVPNRotationPeriodTooSmall, *v.RotationPeriod, minRotationPeriod)
+	}
+
 	err = v.Provider.validate(v.Type, allServers)
 	if err != nil {
 		return fmt.Errorf("provider settings: %w", err)
@@ -51,10 +83,13 @@ func (v *VPN) validate(allServers models.AllServers) (err error) {
 
 func (v *VPN) copy() (copied VPN) {
 	return VPN{
-		Type:      v.Type,
-		Provider:  v.Provider.copy(),
-		OpenVPN:   v.OpenVPN.copy(),
-		Wireguard: v.Wireguard.copy(),
+		Type:              v.Type,
+		Provider:          v.Provider.copy(),
+		OpenVPN:           v.OpenVPN.copy(),
+		Wireguard:         v.Wireguard.copy(),
+		RotationPeriod:    helpers.CopyDurationPtr(v.RotationPeriod),
+		BlocklistCooldown: helpers.CopyDurationPtr(v.BlocklistCooldown),
+		Failover:          v.Failover.copy(),
 	}
 }
 
@@ -63,6 +98,9 @@ func (v *VPN) mergeWith(other VPN) {
 	v.Provider.mergeWith(other.Provider)
 	v.OpenVPN.mergeWith(other.OpenVPN)
 	v.Wireguard.mergeWith(other.Wireguard)
+	v.RotationPeriod = helpers.MergeWithDuration(v.RotationPeriod, other.RotationPeriod)
+	v.BlocklistCooldown = helpers.MergeWithDuration(v.BlocklistCooldown, other.BlocklistCooldown)
+	v.Failover.mergeWith(other.Failover)
 }
 
 func (v *VPN) overrideWith(other VPN) {
@@ -70,6 +108,25 @@ func (v *VPN) overrideWith(other VPN) {
 	v.Provider.overrideWith(other.Provider)
 	v.OpenVPN.overrideWith(other.OpenVPN)
 	v.Wireguard.overrideWith(other.Wireguard)
+	v.RotationPeriod = helpers.OverrideWithDuration(v.RotationPeriod, other.RotationPeriod)
+	v.BlocklistCooldown = helpers.OverrideWithDuration(v.BlocklistCooldown, other.BlocklistCooldown)
+	v.Failover.overrideWith(other.Failover)
+}
+
+// OverrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings, and validates the resulting settings.
+// The receiver is left unchanged if an error is returned.
+func (v *VPN) OverrideWith(other VPN,
+	allServers models.AllServers) (err error) {
+	patchedSettings := v.copy()
+	patchedSettings.overrideWith(other)
+	err = patchedSettings.validate(allServers)
+	if err != nil {
+		return err
+	}
+	*v = patchedSettings
+	return nil
 }
 
 func (v *VPN) setDefaults() {
@@ -77,6 +134,10 @@ func (v *VPN) setDefaults() {
 	v.Provider.setDefaults()
 	v.OpenVPN.setDefaults(*v.Provider.Name)
 	v.Wireguard.setDefaults()
+	v.RotationPeriod = helpers.DefaultDuration(v.RotationPeriod, 0)
+	const defaultBlocklistCooldown = 15 * time.Minute
+	v.BlocklistCooldown = helpers.DefaultDuration(v.BlocklistCooldown, defaultBlocklistCooldown)
+	v.Failover.setDefaults()
 }
 
 func (v VPN) String() string {
@@ -94,5 +155,17 @@ func (v VPN) toLinesNode() (node *gotree.Node) {
 		node.AppendNode(v.Wireguard.toLinesNode())
 	}
 
+	if *v.RotationPeriod > 0 {
+		node.Appendf("Server rotation period: %s", *v.RotationPeriod)
+	}
+
+	if *v.BlocklistCooldown > 0 {
+		node.Appendf("Failing servers blocklist cooldown: %s", *v.BlocklistCooldown)
+	}
+
+	if len(v.Failover.Profiles) > 0 {
+		node.AppendNode(v.Failover.toLinesNode())
+	}
+
 	return node
 }
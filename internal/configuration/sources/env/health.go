@@ -1,17 +1,38 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 )
 
 func (r *Reader) ReadHealth() (health settings.Health, err error) {
 	health.ServerAddress = os.Getenv("HEALTH_SERVER_ADDRESS")
+	health.ServerTLS = readHTTPServerTLS("HEALTH_SERVER_")
 	_, health.TargetAddress = r.getEnvWithRetro("HEALTH_TARGET_ADDRESS", "HEALTH_ADDRESS_TO_PING")
 
+	health.Targets, err = readHealthTargets()
+	if err != nil {
+		return health, err
+	}
+	health.TargetsPolicy = strings.ToLower(os.Getenv("HEALTH_TARGETS_POLICY"))
+
+	health.LeakCheckPeriod, err = envToDurationPtr("HEALTH_LEAK_CHECK_PERIOD")
+	if err != nil {
+		return health, fmt.Errorf("environment variable HEALTH_LEAK_CHECK_PERIOD: %w", err)
+	}
+
+	health.WireguardHandshakeTimeout, err = envToDurationPtr("HEALTH_WIREGUARD_HANDSHAKE_TIMEOUT")
+	if err != nil {
+		return health, fmt.Errorf("environment variable HEALTH_WIREGUARD_HANDSHAKE_TIMEOUT: %w", err)
+	}
+
 	health.VPN.Initial, err = r.readDurationWithRetro(
 		"HEALTH_VPN_DURATION_INITIAL",
 		"HEALTH_OPENVPN_DURATION_INITIAL")
@@ -26,9 +47,38 @@ func (r *Reader) ReadHealth() (health settings.Health, err error) {
 		return health, err
 	}
 
+	health.Recovery, err = readHealthRecovery()
+	if err != nil {
+		return health, fmt.Errorf("health recovery: %w", err)
+	}
+
 	return health, nil
 }
 
+func readHealthRecovery() (recovery settings.HealthRecovery, err error) {
+	recovery.Restarts, err = envToUint8Ptr("HEALTH_RECOVERY_RESTARTS")
+	if err != nil {
+		return recovery, fmt.Errorf("environment variable HEALTH_RECOVERY_RESTARTS: %w", err)
+	}
+
+	recovery.ServerSwitches, err = envToUint8Ptr("HEALTH_RECOVERY_SERVER_SWITCHES")
+	if err != nil {
+		return recovery, fmt.Errorf("environment variable HEALTH_RECOVERY_SERVER_SWITCHES: %w", err)
+	}
+
+	recovery.ExcludeDuration, err = envToDurationPtr("HEALTH_RECOVERY_EXCLUDE_DURATION")
+	if err != nil {
+		return recovery, fmt.Errorf("environment variable HEALTH_RECOVERY_EXCLUDE_DURATION: %w", err)
+	}
+
+	recovery.TCPFallback, err = envToBoolPtr("HEALTH_RECOVERY_TCP_FALLBACK")
+	if err != nil {
+		return recovery, fmt.Errorf("environment variable HEALTH_RECOVERY_TCP_FALLBACK: %w", err)
+	}
+
+	return recovery, nil
+}
+
 func (r *Reader) readDurationWithRetro(envKey, retroEnvKey string) (d *time.Duration, err error) {
 	envKey, s := r.getEnvWithRetro(envKey, retroEnvKey)
 	if s == "" {
@@ -43,3 +93,77 @@ func (r *Reader) readDurationWithRetro(envKey, retroEnvKey string) (d *time.Dura
 
 	return d, nil
 }
+
+var (
+	ErrHealthTargetNotValid       = errors.New("health target is not valid")
+	ErrHealthTargetTypeUnknown    = errors.New("health target type is unknown")
+	ErrHealthTargetStatusNotValid = errors.New("health target HTTP status is not valid")
+)
+
+// readHealthTargets reads the comma separated list of health targets,
+// where each target is in the form type:address, for example
+// tcp:cloudflare.com:443, dns:github.com, icmp:1.1.1.1 or
+// http:https://example.com/status|204 where the optional
+// |204 suffix is the HTTP response status expected.
+// Commas in a target address must be percent encoded as %2C.
+func readHealthTargets() (targets []settings.HealthTarget, err error) {
+	csv := os.Getenv("HEALTH_TARGETS")
+	if csv == "" {
+		return nil, nil
+	}
+
+	targets, err = parseHealthTargets(csv)
+	if err != nil {
+		return nil, fmt.Errorf("environment variable HEALTH_TARGETS: %w", err)
+	}
+	return targets, nil
+}
+
+func parseHealthTargets(csv string) (targets []settings.HealthTarget, err error) {
+	fields := strings.Split(csv, ",")
+	targets = make([]settings.HealthTarget, len(fields))
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		// A field without a known type is most likely the
+		// end of the previous target address containing a comma.
+		const maxParts = 2
+		targetType := strings.ToLower(strings.SplitN(field, ":", maxParts)[0])
+		switch targetType {
+		case constants.HealthProbeTCP, constants.HealthProbeHTTP,
+			constants.HealthProbeDNS, constants.HealthProbeICMP:
+		default:
+			return nil, fmt.Errorf("%w: %q must be in the form type:address "+
+				"where type is tcp, http, dns or icmp, and commas in an address "+
+				"must be percent encoded as %%2C",
+				ErrHealthTargetTypeUnknown, field)
+		}
+
+		targets[i], err = parseHealthTarget(field)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return targets, nil
+}
+
+func parseHealthTarget(s string) (target settings.HealthTarget, err error) {
+	const maxParts = 2
+	parts := strings.SplitN(s, ":", maxParts)
+	if len(parts) != maxParts {
+		return target, fmt.Errorf("%w: %q must be in the form type:address",
+			ErrHealthTargetNotValid, s)
+	}
+	target.Type = strings.ToLower(parts[0])
+	target.Address = parts[1]
+
+	if i := strings.LastIndex(target.Address, "|"); i >= 0 {
+		statusString := target.Address[i+1:]
+		target.Address = target.Address[:i]
+		target.ExpectedStatus, err = strconv.Atoi(statusString)
+		if err != nil {
+			return target, fmt.Errorf("%w: %s", ErrHealthTargetStatusNotValid, statusString)
+		}
+	}
+
+	return target, nil
+}
@@ -0,0 +1,119 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHealthTarget(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		target     settings.HealthTarget
+		errWrapped error
+		errMessage string
+	}{
+		"missing type": {
+			s:          "cloudflare.com",
+			errWrapped: ErrHealthTargetNotValid,
+			errMessage: `health target is not valid: "cloudflare.com" must be in the form type:address`,
+		},
+		"tcp with port": {
+			s: "tcp:cloudflare.com:443",
+			target: settings.HealthTarget{
+				Type:    "tcp",
+				Address: "cloudflare.com:443",
+			},
+		},
+		"upper case type": {
+			s: "DNS:github.com",
+			target: settings.HealthTarget{
+				Type:    "dns",
+				Address: "github.com",
+			},
+		},
+		"http with expected status": {
+			s: "http:https://example.com/status|204",
+			target: settings.HealthTarget{
+				Type:           "http",
+				Address:        "https://example.com/status",
+				ExpectedStatus: 204,
+			},
+		},
+		"http with bad expected status": {
+			s: "http:https://example.com|abc",
+			target: settings.HealthTarget{
+				Type:    "http",
+				Address: "https://example.com",
+			},
+			errWrapped: ErrHealthTargetStatusNotValid,
+			errMessage: "health target HTTP status is not valid: abc",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := parseHealthTarget(testCase.s)
+
+			assert.Equal(t, testCase.target, target)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_parseHealthTargets(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		csv        string
+		targets    []settings.HealthTarget
+		errWrapped error
+		errMessage string
+	}{
+		"multiple targets": {
+			csv: "tcp:cloudflare.com:443, http:https://example.com/a%2Cb?c=1%2C2|204",
+			targets: []settings.HealthTarget{
+				{Type: "tcp", Address: "cloudflare.com:443"},
+				{Type: "http", Address: "https://example.com/a%2Cb?c=1%2C2", ExpectedStatus: 204},
+			},
+		},
+		"comma in address": {
+			csv:        "http:https://example.com/?a=1,2,dns:github.com",
+			errWrapped: ErrHealthTargetTypeUnknown,
+			errMessage: `health target type is unknown: "2" must be in the form ` +
+				"type:address where type is tcp, http, dns or icmp, and commas " +
+				"in an address must be percent encoded as %2C",
+		},
+		"comma in address with colon": {
+			csv:        "http:https://example.com/?a=1,b:2",
+			errWrapped: ErrHealthTargetTypeUnknown,
+			errMessage: `health target type is unknown: "b:2" must be in the form ` +
+				"type:address where type is tcp, http, dns or icmp, and commas " +
+				"in an address must be percent encoded as %2C",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			targets, err := parseHealthTargets(testCase.csv)
+
+			assert.Equal(t, testCase.targets, targets)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readHooks() (hooks settings.Hooks, err error) {
+	hooks.TunnelUp = envToStringPtr("HOOK_TUNNEL_UP")
+	hooks.TunnelDown = envToStringPtr("HOOK_TUNNEL_DOWN")
+	hooks.PortForwarded = envToStringPtr("HOOK_PORT_FORWARDED")
+	hooks.PublicIPChanged = envToStringPtr("HOOK_PUBLIC_IP_CHANGED")
+
+	hooks.Timeout, err = envToDurationPtr("HOOK_TIMEOUT")
+	if err != nil {
+		return hooks, fmt.Errorf("environment variable HOOK_TIMEOUT: %w", err)
+	}
+
+	return hooks, nil
+}
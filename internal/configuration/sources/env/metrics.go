@@ -0,0 +1,20 @@
+package env
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gluetun/internal/metrics"
+)
+
+func readMetrics() (settings metrics.Settings, err error) {
+	settings.Enabled, err = envToBoolPtr("METRICS_ENABLED")
+	if err != nil {
+		return settings, fmt.Errorf("environment variable METRICS_ENABLED: %w", err)
+	}
+
+	settings.HTTPServer.Address = os.Getenv("METRICS_HTTP_SERVER_ADDRESS")
+	settings.HTTPServer.TLS = readHTTPServerTLS("METRICS_HTTP_SERVER_")
+
+	return settings, nil
+}
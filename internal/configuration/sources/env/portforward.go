@@ -23,5 +23,15 @@ func (r *Reader) readPortForward() (
 		portForwarding.Filepath = stringPtr(value)
 	}
 
+	portForwarding.QBittorrent = readPortForwardingClient("PORT_FORWARDING_QBITTORRENT_")
+	portForwarding.Transmission = readPortForwardingClient("PORT_FORWARDING_TRANSMISSION_")
+
 	return portForwarding, nil
 }
+
+func readPortForwardingClient(prefix string) (client settings.PortForwardingClient) {
+	client.Address = envToStringPtr(prefix + "ADDRESS")
+	client.User = envToStringPtr(prefix + "USER")
+	client.Password = envToStringPtr(prefix + "PASSWORD")
+	return client
+}
@@ -24,6 +24,7 @@ func readPprof() (settings pprof.Settings, err error) {
 	}
 
 	settings.HTTPServer.Address = os.Getenv("PPROF_HTTP_SERVER_ADDRESS")
+	settings.HTTPServer.TLS = readHTTPServerTLS("PPROF_HTTP_SERVER_")
 
 	return settings, nil
 }
@@ -46,6 +46,11 @@ func (r *Reader) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	settings.Hooks, err = readHooks()
+	if err != nil {
+		return settings, err
+	}
+
 	settings.HTTPProxy, err = r.readHTTPProxy()
 	if err != nil {
 		return settings, err
@@ -91,6 +96,11 @@ func (r *Reader) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	settings.Metrics, err = readMetrics()
+	if err != nil {
+		return settings, err
+	}
+
 	return settings, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/govalid/binary"
 )
 
@@ -15,6 +16,8 @@ func (r *Reader) readControlServer() (controlServer settings.ControlServer, err
 	}
 
 	controlServer.Address = r.readControlServerAddress()
+	controlServer.Auth = readControlServerAuth()
+	controlServer.TLS = readHTTPServerTLS("HTTP_CONTROL_SERVER_")
 
 	return controlServer, nil
 }
@@ -47,3 +50,26 @@ func (r *Reader) readControlServerAddress() (address *string) {
 	*address = ":" + s
 	return address
 }
+
+func readControlServerAuth() (auth settings.ControlServerAuth) {
+	auth.Admin = readControlServerRole("ADMIN")
+	auth.ReadOnly = readControlServerRole("READONLY")
+	return auth
+}
+
+func readControlServerRole(role string) (controlServerRole settings.ControlServerRole) {
+	const prefix = "HTTP_CONTROL_SERVER_"
+	controlServerRole.APIKey = envToStringPtr(prefix + role + "_API_KEY")
+	controlServerRole.User = envToStringPtr(prefix + role + "_USER")
+	controlServerRole.Password = envToStringPtr(prefix + role + "_PASSWORD")
+	return controlServerRole
+}
+
+// readHTTPServerTLS reads the TLS settings of an HTTP server
+// from environment variables with the given prefix.
+func readHTTPServerTLS(prefix string) (tls httpserver.TLS) {
+	tls.CertFilepath = os.Getenv(prefix + "TLS_CERT_FILEPATH")
+	tls.KeyFilepath = os.Getenv(prefix + "TLS_KEY_FILEPATH")
+	tls.ClientCAFilepath = os.Getenv(prefix + "TLS_CLIENT_CA_FILEPATH")
+	return tls
+}
@@ -78,6 +78,12 @@ func (r *Reader) readServerSelection(vpnProvider, vpnType string) (
 		return ss, fmt.Errorf("environment variable FREE_ONLY: %w", err)
 	}
 
+	// ProtonVPN only
+	ss.PortForwardOnly, err = envToBoolPtr("PORT_FORWARD_ONLY")
+	if err != nil {
+		return ss, fmt.Errorf("environment variable PORT_FORWARD_ONLY: %w", err)
+	}
+
 	// VPNUnlimited only
 	ss.MultiHopOnly, err = envToBoolPtr("MULTIHOP_ONLY")
 	if err != nil {
@@ -90,6 +96,8 @@ func (r *Reader) readServerSelection(vpnProvider, vpnType string) (
 		return ss, fmt.Errorf("environment variable STREAM_ONLY: %w", err)
 	}
 
+	ss.Strategy = strings.ToLower(os.Getenv("SERVER_SELECTION_STRATEGY"))
+
 	ss.OpenVPN, err = r.readOpenVPNSelection()
 	if err != nil {
 		return ss, err
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
@@ -26,5 +27,38 @@ func (r *Reader) readVPN() (vpn settings.VPN, err error) {
 		return vpn, fmt.Errorf("wireguard: %w", err)
 	}
 
+	vpn.RotationPeriod, err = readVPNRotationPeriod()
+	if err != nil {
+		return vpn, err
+	}
+
+	vpn.BlocklistCooldown, err = envToDurationPtr("VPN_BLOCKLIST_COOLDOWN")
+	if err != nil {
+		return vpn, fmt.Errorf("environment variable VPN_BLOCKLIST_COOLDOWN: %w", err)
+	}
+
+	vpn.Failover.Threshold, err = envToDurationPtr("VPN_FAILOVER_THRESHOLD")
+	if err != nil {
+		return vpn, fmt.Errorf("environment variable VPN_FAILOVER_THRESHOLD: %w", err)
+	}
+
+	vpn.Failover.ReturnPeriod, err = envToDurationPtr("VPN_FAILOVER_RETURN_PERIOD")
+	if err != nil {
+		return vpn, fmt.Errorf("environment variable VPN_FAILOVER_RETURN_PERIOD: %w", err)
+	}
+
 	return vpn, nil
 }
+
+func readVPNRotationPeriod() (period *time.Duration, err error) {
+	s := os.Getenv("VPN_ROTATION_PERIOD")
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+	period = new(time.Duration)
+	*period, err = time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("environment variable VPN_ROTATION_PERIOD: %w", err)
+	}
+	return period, nil
+}
@@ -1,16 +1,45 @@
 package files
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 )
 
+// VPNProfilesPath is the filepath of the JSON encoded list
+// of VPN profiles to fail over to.
+const VPNProfilesPath = "/gluetun/profiles.json"
+
 func (r *Reader) readVPN() (vpn settings.VPN, err error) {
 	vpn.OpenVPN, err = r.readOpenVPN()
 	if err != nil {
 		return vpn, fmt.Errorf("OpenVPN: %w", err)
 	}
 
+	vpn.Failover.Profiles, err = readVPNProfiles(VPNProfilesPath)
+	if err != nil {
+		return vpn, fmt.Errorf("VPN profiles: %w", err)
+	}
+
 	return vpn, nil
 }
+
+func readVPNProfiles(filepath string) (profiles []settings.VPNProfile, err error) {
+	content, err := ReadFromFile(filepath)
+	if err != nil {
+		return nil, err
+	} else if content == nil {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(*content))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&profiles)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode file %s: %w", filepath, err)
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readPortForwarding() (settings settings.PortForwarding, err error) {
+	settings.QBittorrent, err = readPortForwardingClient("QBITTORRENT", "qbittorrent")
+	if err != nil {
+		return settings, fmt.Errorf("cannot read qBittorrent client secrets: %w", err)
+	}
+
+	settings.Transmission, err = readPortForwardingClient("TRANSMISSION", "transmission")
+	if err != nil {
+		return settings, fmt.Errorf("cannot read Transmission client secrets: %w", err)
+	}
+
+	return settings, nil
+}
+
+func readPortForwardingClient(envKeyName, fileName string) (
+	client settings.PortForwardingClient, err error) {
+	client.User, err = readSecretFileAsStringPtr(
+		"PORT_FORWARDING_"+envKeyName+"_USER_SECRETFILE",
+		"/run/secrets/"+fileName+"_user",
+	)
+	if err != nil {
+		return client, fmt.Errorf("cannot read user file: %w", err)
+	}
+
+	client.Password, err = readSecretFileAsStringPtr(
+		"PORT_FORWARDING_"+envKeyName+"_PASSWORD_SECRETFILE",
+		"/run/secrets/"+fileName+"_password",
+	)
+	if err != nil {
+		return client, fmt.Errorf("cannot read password file: %w", err)
+	}
+
+	return client, nil
+}
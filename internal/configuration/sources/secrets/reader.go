@@ -32,5 +32,10 @@ func (r *Reader) Read() (settings settings.Settings, err error) {
 		return settings, err
 	}
 
+	settings.ControlServer, err = readControlServer()
+	if err != nil {
+		return settings, err
+	}
+
 	return settings, nil
 }
@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func readControlServer() (controlServer settings.ControlServer, err error) {
+	controlServer.Auth.Admin, err = readControlServerRole("ADMIN")
+	if err != nil {
+		return controlServer, fmt.Errorf("cannot read admin role: %w", err)
+	}
+
+	controlServer.Auth.ReadOnly, err = readControlServerRole("READONLY")
+	if err != nil {
+		return controlServer, fmt.Errorf("cannot read read only role: %w", err)
+	}
+
+	return controlServer, nil
+}
+
+func readControlServerRole(role string) (
+	controlServerRole settings.ControlServerRole, err error) {
+	const envPrefix = "HTTP_CONTROL_SERVER_"
+	pathPrefix := "/run/secrets/httpcontrolserver_" + strings.ToLower(role) + "_"
+
+	controlServerRole.APIKey, err = readSecretFileAsStringPtr(
+		envPrefix+role+"_API_KEY_SECRETFILE",
+		pathPrefix+"api_key",
+	)
+	if err != nil {
+		return controlServerRole, fmt.Errorf("cannot read API key secret file: %w", err)
+	}
+
+	controlServerRole.User, err = readSecretFileAsStringPtr(
+		envPrefix+role+"_USER_SECRETFILE",
+		pathPrefix+"user",
+	)
+	if err != nil {
+		return controlServerRole, fmt.Errorf("cannot read user secret file: %w", err)
+	}
+
+	controlServerRole.Password, err = readSecretFileAsStringPtr(
+		envPrefix+role+"_PASSWORD_SECRETFILE",
+		pathPrefix+"password",
+	)
+	if err != nil {
+		return controlServerRole, fmt.Errorf("cannot read password secret file: %w", err)
+	}
+
+	return controlServerRole, nil
+}
@@ -12,5 +12,10 @@ func readVPN() (vpn settings.VPN, err error) {
 		return vpn, fmt.Errorf("cannot read OpenVPN settings: %w", err)
 	}
 
+	vpn.Provider.PortForwarding, err = readPortForwarding()
+	if err != nil {
+		return vpn, fmt.Errorf("cannot read port forwarding settings: %w", err)
+	}
+
 	return vpn, nil
 }
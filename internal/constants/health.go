@@ -0,0 +1,23 @@
+package constants
+
+const (
+	// HealthProbeTCP is the health check probe type
+	// dialing a TCP address.
+	HealthProbeTCP = "tcp"
+	// HealthProbeHTTP is the health check probe type
+	// sending an HTTP GET request and checking the response status.
+	HealthProbeHTTP = "http"
+	// HealthProbeDNS is the health check probe type
+	// resolving a hostname.
+	HealthProbeDNS = "dns"
+	// HealthProbeICMP is the health check probe type
+	// sending an ICMP echo request.
+	HealthProbeICMP = "icmp"
+)
+
+const (
+	// HealthPolicyAll requires all the health check targets to pass.
+	HealthPolicyAll = "all"
+	// HealthPolicyAny requires at least one health check target to pass.
+	HealthPolicyAny = "any"
+)
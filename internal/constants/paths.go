@@ -5,4 +5,10 @@ const (
 	OpenVPNAuthConf = "/etc/openvpn/auth.conf"
 	// ServersData is the server information filepath.
 	ServersData = "/gluetun/servers.json"
+	// Blocklist is the filepath of the servers temporarily
+	// excluded from the server selection.
+	Blocklist = "/gluetun/blocklist.json"
+	// LastConnection is the filepath of the last VPN
+	// connection which reached a healthy state.
+	LastConnection = "/gluetun/lastconnection.json"
 )
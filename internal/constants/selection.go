@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// SelectionRandom picks a random connection.
+	SelectionRandom = "random"
+	// SelectionLowestLatency picks the connection with the lowest
+	// latency out of a sample of connections.
+	SelectionLowestLatency = "lowest-latency"
+	// SelectionWeighted picks a random connection out of a sample
+	// of connections, favoring connections with a lower latency.
+	SelectionWeighted = "weighted"
+)
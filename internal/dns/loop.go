@@ -11,6 +11,7 @@ import (
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns/state"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 )
@@ -47,14 +48,15 @@ type Loop struct {
 const defaultBackoffTime = 10 * time.Second
 
 func NewLoop(conf unbound.Configurator, settings settings.DNS,
-	client *http.Client, logger Logger) *Loop {
+	client *http.Client, logger Logger, publisher events.Publisher) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 	updateTicker := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped,
+		"dns", publisher)
 	state := state.New(statusManager, settings, updateTicker)
 
 	return &Loop{
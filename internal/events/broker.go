@@ -0,0 +1,86 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+var _ PublishSubscriber = (*Broker)(nil)
+
+type PublishSubscriber interface {
+	Publisher
+	Subscriber
+}
+
+type Publisher interface {
+	Publish(component string, eventType Type, data interface{})
+}
+
+type Subscriber interface {
+	Subscribe() (events <-chan Event, unsubscribe func())
+}
+
+// Broker forwards published events to all its subscribers.
+type Broker struct {
+	subscribers   map[chan Event]struct{}
+	subscribersMu sync.RWMutex
+	bufferSize    int
+	logger        Warner
+	timeNow       func() time.Time
+}
+
+func New(logger Warner) *Broker {
+	const defaultBufferSize = 32
+	return &Broker{
+		subscribers: make(map[chan Event]struct{}),
+		bufferSize:  defaultBufferSize,
+		logger:      logger,
+		timeNow:     time.Now,
+	}
+}
+
+// Publish sends an event to each subscriber without blocking.
+// If a subscriber is too slow and its buffer is full, the event
+// is dropped for this subscriber and a warning is logged.
+func (b *Broker) Publish(component string, eventType Type, data interface{}) {
+	event := Event{
+		Time:      b.timeNow(),
+		Component: component,
+		Type:      eventType,
+		Data:      data,
+	}
+
+	b.subscribersMu.RLock()
+	defer b.subscribersMu.RUnlock()
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			b.logger.Warn("dropping " + string(eventType) + " event from " +
+				component + " for a subscriber too slow to receive it")
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events published
+// after this call, and an unsubscribe function which must be
+// called once the caller is no longer reading from the channel.
+func (b *Broker) Subscribe() (events <-chan Event, unsubscribe func()) {
+	subscriber := make(chan Event, b.bufferSize)
+
+	b.subscribersMu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.subscribersMu.Unlock()
+
+	unsubscribe = func() {
+		b.subscribersMu.Lock()
+		defer b.subscribersMu.Unlock()
+		if _, ok := b.subscribers[subscriber]; !ok {
+			return // already unsubscribed
+		}
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+
+	return subscriber, unsubscribe
+}
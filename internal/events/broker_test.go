@@ -0,0 +1,59 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWarner struct {
+	lines []string
+}
+
+func (w *testWarner) Warn(s string) { w.lines = append(w.lines, s) }
+
+func Test_Broker(t *testing.T) {
+	t.Parallel()
+
+	logger := &testWarner{}
+	broker := New(logger)
+	broker.bufferSize = 1
+	broker.timeNow = func() time.Time { return time.Unix(1, 0) }
+
+	// No subscriber, the event is dropped
+	broker.Publish("vpn", TypeStatus, "running")
+
+	eventsA, unsubscribeA := broker.Subscribe()
+	eventsB, unsubscribeB := broker.Subscribe()
+
+	broker.Publish("dns", TypeStatus, "stopped")
+	// Second event is dropped since the buffer size is 1
+	broker.Publish("dns", TypeStatus, "running")
+	expectedWarnings := []string{
+		"dropping status event from dns for a subscriber too slow to receive it",
+		"dropping status event from dns for a subscriber too slow to receive it",
+	}
+	assert.Equal(t, expectedWarnings, logger.lines)
+
+	expectedEvent := Event{
+		Time:      time.Unix(1, 0),
+		Component: "dns",
+		Type:      TypeStatus,
+		Data:      "stopped",
+	}
+	assert.Equal(t, expectedEvent, <-eventsA)
+	assert.Equal(t, expectedEvent, <-eventsB)
+
+	unsubscribeA()
+	unsubscribeA() // no-op
+	_, ok := <-eventsA
+	assert.False(t, ok)
+
+	broker.Publish("updater", TypeUpdaterCompleted, nil)
+	event := <-eventsB
+	assert.Equal(t, "updater", event.Component)
+
+	unsubscribeB()
+	assert.Empty(t, broker.subscribers)
+}
@@ -0,0 +1,86 @@
+// Package events defines a broker to publish and subscribe to
+// events happening in the program, such as loop status changes.
+package events
+
+import (
+	"net"
+	"time"
+)
+
+// Type is the type of an event.
+type Type string
+
+const (
+	// TypeStatus is the type for a loop status change event.
+	// Its data is the new status of type models.LoopStatus.
+	TypeStatus Type = "status"
+	// TypePublicIP is the type for a public IP address change event.
+	// Its data is the new public IP information data.
+	TypePublicIP Type = "publicip"
+	// TypePortForwarded is the type for a forwarded port change event.
+	// Its data is the new port forwarded as an uint16, which is 0
+	// if no port is forwarded anymore.
+	TypePortForwarded Type = "portforwarded"
+	// TypeHealth is the type for a health change event.
+	// Its data is a HealthData object.
+	TypeHealth Type = "health"
+	// TypeRecovery is the type for a recovery action event,
+	// emitted when the VPN is unhealthy for too long.
+	// Its data is a RecoveryData object.
+	TypeRecovery Type = "recovery"
+	// TypeTunnelUp is the type for an event emitted when
+	// the VPN tunnel is up. Its data is a TunnelData object.
+	TypeTunnelUp Type = "tunnelup"
+	// TypeTunnelDown is the type for an event emitted when
+	// the VPN connection is stopped. Its data is a TunnelData
+	// object for the VPN connection stopped.
+	TypeTunnelDown Type = "tunneldown"
+	// TypeUpdaterCompleted is the type for an event emitted
+	// when the servers updater completes an update.
+	TypeUpdaterCompleted Type = "updatercompleted"
+)
+
+// Event is an event published to subscribers.
+type Event struct {
+	// Time is the time the event was published at.
+	Time time.Time `json:"time"`
+	// Component is the component name emitting the event,
+	// for example "vpn" or "dns".
+	Component string `json:"component"`
+	// Type is the event type.
+	Type Type `json:"type"`
+	// Data is the event data specific to the event type.
+	Data interface{} `json:"data"`
+}
+
+// HealthData is the data for an event of type TypeHealth.
+type HealthData struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RecoveryData is the data for an event of type TypeRecovery.
+type RecoveryData struct {
+	// Step is the recovery step taken, which can be
+	// "restart", "switch-server" or "tcp-fallback".
+	Step string `json:"step"`
+	// Attempt is the number of recovery attempts since
+	// the program was last healthy, including this one.
+	Attempt uint `json:"attempt"`
+	// Outcome is the outcome of the recovery step.
+	Outcome string `json:"outcome"`
+}
+
+// TunnelData is the data for an event of type TypeTunnelUp
+// or TypeTunnelDown.
+type TunnelData struct {
+	// VPNType is the VPN type, "openvpn" or "wireguard".
+	VPNType string `json:"vpn_type"`
+	// Interface is the VPN network interface name.
+	Interface string `json:"interface"`
+	// ServerIP is the VPN server IP address.
+	ServerIP net.IP `json:"server_ip"`
+	// ServerName is the VPN server hostname,
+	// which can be empty for some providers.
+	ServerName string `json:"server_name,omitempty"`
+}
@@ -0,0 +1,5 @@
+package events
+
+type Warner interface {
+	Warn(s string)
+}
@@ -20,6 +20,8 @@ type Configurator interface {
 	VPNConnectionSetter
 	PortAllower
 	OutboundSubnetsSetter
+	ProbeAllower
+	StateGetter
 }
 
 type Config struct { //nolint:maligned
@@ -40,6 +42,7 @@ type Config struct { //nolint:maligned
 	vpnConnection     models.Connection
 	vpnIntf           string
 	outboundSubnets   []net.IPNet
+	probeConnections  []models.Connection
 	allowedInputPorts map[uint16]map[string]struct{} // port to interfaces set mapping
 	stateMutex        sync.Mutex
 }
@@ -0,0 +1,55 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type ProbeAllower interface {
+	SetProbeConnections(ctx context.Context, connections []models.Connection) (err error)
+}
+
+// SetProbeConnections allows outbound traffic to the connections given,
+// in order to measure their latency before connecting to one of them.
+// The connections previously set are no longer allowed, and calling it
+// with no connection removes all the probe rules.
+func (c *Config) SetProbeConnections(ctx context.Context,
+	connections []models.Connection) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !c.enabled {
+		c.probeConnections = nil
+		return nil
+	}
+
+	c.removeProbeConnections(ctx)
+
+	const remove = false
+	for _, connection := range connections {
+		for _, defaultRoute := range c.defaultRoutes {
+			err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove)
+			if err != nil {
+				return fmt.Errorf("cannot allow output traffic to probe connection: %w", err)
+			}
+		}
+		c.probeConnections = append(c.probeConnections, connection)
+	}
+
+	return nil
+}
+
+func (c *Config) removeProbeConnections(ctx context.Context) {
+	const remove = true
+	for _, connection := range c.probeConnections {
+		for _, defaultRoute := range c.defaultRoutes {
+			err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove)
+			if err != nil {
+				c.logger.Error("cannot remove probe connection rule: " + err.Error())
+			}
+		}
+	}
+	c.probeConnections = nil
+}
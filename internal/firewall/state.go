@@ -0,0 +1,53 @@
+package firewall
+
+import (
+	"net"
+	"sort"
+)
+
+type StateGetter interface {
+	GetState() (state State)
+}
+
+// State is a copy of the firewall internal state.
+type State struct {
+	Enabled bool
+	// VPNInterface is the VPN network interface name,
+	// and is empty if the VPN is not connected.
+	VPNInterface string
+	// DefaultInterfaces are the network interfaces
+	// of the default routes.
+	DefaultInterfaces []string
+	OutboundSubnets   []net.IPNet
+	// AllowedInputPorts maps each allowed input port
+	// to the sorted network interfaces it is allowed on.
+	AllowedInputPorts map[uint16][]string
+}
+
+func (c *Config) GetState() (state State) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	state.Enabled = c.enabled
+	state.VPNInterface = c.vpnIntf
+
+	state.DefaultInterfaces = make([]string, len(c.defaultRoutes))
+	for i, defaultRoute := range c.defaultRoutes {
+		state.DefaultInterfaces[i] = defaultRoute.NetInterface
+	}
+
+	state.OutboundSubnets = make([]net.IPNet, len(c.outboundSubnets))
+	copy(state.OutboundSubnets, c.outboundSubnets)
+
+	state.AllowedInputPorts = make(map[uint16][]string, len(c.allowedInputPorts))
+	for port, interfacesSet := range c.allowedInputPorts {
+		interfaces := make([]string, 0, len(interfacesSet))
+		for netInterface := range interfacesSet {
+			interfaces = append(interfaces, netInterface)
+		}
+		sort.Strings(interfaces)
+		state.AllowedInputPorts[port] = interfaces
+	}
+
+	return state
+}
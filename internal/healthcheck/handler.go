@@ -4,10 +4,16 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"time"
 )
 
 type handler struct {
-	healthErr   error
+	healthErr    error
+	probeResults []ProbeResult
+	// loopBeat is the start time of the last
+	// iteration of the health check loop.
+	loopBeat    time.Time
+	recovery    Recovery
 	healthErrMu sync.RWMutex
 }
 
@@ -42,3 +48,39 @@ func (h *handler) getErr() (err error) {
 	defer h.healthErrMu.RUnlock()
 	return h.healthErr
 }
+
+func (h *handler) setResults(results []ProbeResult) {
+	h.healthErrMu.Lock()
+	defer h.healthErrMu.Unlock()
+	h.probeResults = results
+}
+
+func (h *handler) getResults() (results []ProbeResult) {
+	h.healthErrMu.RLock()
+	defer h.healthErrMu.RUnlock()
+	return h.probeResults
+}
+
+func (h *handler) setLoopBeat(t time.Time) {
+	h.healthErrMu.Lock()
+	defer h.healthErrMu.Unlock()
+	h.loopBeat = t
+}
+
+func (h *handler) getLoopBeat() (t time.Time) {
+	h.healthErrMu.RLock()
+	defer h.healthErrMu.RUnlock()
+	return h.loopBeat
+}
+
+func (h *handler) setRecovery(recovery Recovery) {
+	h.healthErrMu.Lock()
+	defer h.healthErrMu.Unlock()
+	h.recovery = recovery
+}
+
+func (h *handler) getRecovery() (recovery Recovery) {
+	h.healthErrMu.RLock()
+	defer h.healthErrMu.RUnlock()
+	return h.recovery
+}
@@ -6,7 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
 )
 
 func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
@@ -15,26 +20,53 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 
 	for {
+		s.handler.setLoopBeat(time.Now())
 		previousErr := s.handler.getErr()
 
 		const healthcheckTimeout = 3 * time.Second
 		healthcheckCtx, healthcheckCancel := context.WithTimeout(
 			ctx, healthcheckTimeout)
+		startTime := time.Now()
 		err := s.healthCheck(healthcheckCtx)
+		if handshakeErr := s.checkWireguardHandshake(); handshakeErr != nil {
+			// a stalled tunnel gives a more precise diagnostic
+			// than the probes failing.
+			err = handshakeErr
+		}
+		s.metrics.HealthcheckDone(time.Since(startTime), err)
+		for _, result := range s.handler.getResults() {
+			s.metrics.ProbeDone(result.Target.Type, result.Target.Address,
+				result.Latency, result.Err)
+		}
 		healthcheckCancel()
 
+		if err == nil {
+			err = s.leakError(ctx)
+		}
+
 		s.handler.setErr(err)
 
 		if previousErr != nil && err == nil {
 			s.logger.Info("healthy!")
+			s.publisher.Publish("healthcheck", events.TypeHealth,
+				events.HealthData{Healthy: true})
 			s.vpn.healthyTimer.Stop()
 			s.vpn.healthyWait = *s.config.VPN.Initial
+			s.handler.setRecovery(Recovery{})
 		} else if previousErr == nil && err != nil {
 			s.logger.Info("unhealthy: " + err.Error())
+			s.publisher.Publish("healthcheck", events.TypeHealth,
+				events.HealthData{Error: err.Error()})
 			s.vpn.healthyTimer.Stop()
 			s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 		}
 
+		if err == nil {
+			// the VPN connection may have changed without
+			// the program becoming unhealthy in between.
+			s.vpn.looper.ReportHealthy()
+		}
+
 		if err != nil { // try again after 1 second
 			timer := time.NewTimer(time.Second)
 			select {
@@ -46,6 +78,7 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 			case <-timer.C:
 			case <-s.vpn.healthyTimer.C:
 				s.onUnhealthyVPN(ctx)
+				s.leakCheckAfterRecovery()
 			}
 			continue
 		}
@@ -64,26 +97,59 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	}
 }
 
+var ErrNotEnoughTargetsPassed = errors.New("not enough health targets passed")
+
 func (s *Server) healthCheck(ctx context.Context) (err error) {
 	// TODO use mullvad API if current provider is Mullvad
 
-	address, err := makeAddressToDial(s.config.TargetAddress)
+	required, err := s.config.RequiredTargets()
 	if err != nil {
 		return err
 	}
 
-	const dialNetwork = "tcp4"
-	connection, err := s.dialer.DialContext(ctx, dialNetwork, address)
-	if err != nil {
-		return fmt.Errorf("cannot dial: %w", err)
+	results := s.probeTargets(ctx)
+	s.handler.setResults(results)
+
+	passed := 0
+	errorMessages := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Err == nil {
+			passed++
+			continue
+		}
+		errorMessages = append(errorMessages,
+			result.Target.String()+": "+result.Err.Error())
 	}
 
-	err = connection.Close()
-	if err != nil {
-		return fmt.Errorf("cannot close connection: %w", err)
+	if passed >= required {
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf("%w: %d of %d passed and %d required: %s",
+		ErrNotEnoughTargetsPassed, passed, len(results), required,
+		strings.Join(errorMessages, "; "))
+}
+
+// probeTargets probes all the health targets in parallel
+// and returns their results in the order of the targets.
+func (s *Server) probeTargets(ctx context.Context) (results []ProbeResult) {
+	results = make([]ProbeResult, len(s.config.Targets))
+	var wg sync.WaitGroup
+	for i, target := range s.config.Targets {
+		wg.Add(1)
+		go func(i int, target settings.HealthTarget) {
+			defer wg.Done()
+			startTime := time.Now()
+			err := s.probe(ctx, target)
+			results[i] = ProbeResult{
+				Target:  target,
+				Latency: time.Since(startTime),
+				Err:     err,
+			}
+		}(i, target)
+	}
+	wg.Wait()
+	return results
 }
 
 func makeAddressToDial(address string) (addressToDial string, err error) {
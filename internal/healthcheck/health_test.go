@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,9 +25,13 @@ func Test_Server_healthCheck(t *testing.T) {
 		const address = "cloudflare.com:443"
 
 		server := &Server{
-			dialer: dialer,
+			handler: newHandler(),
+			dialer:  dialer,
 			config: settings.Health{
-				TargetAddress: address,
+				Targets: []settings.HealthTarget{
+					{Type: constants.HealthProbeTCP, Address: address},
+				},
+				TargetsPolicy: constants.HealthPolicyAll,
 			},
 		}
 
@@ -51,9 +58,13 @@ func Test_Server_healthCheck(t *testing.T) {
 
 		dialer := &net.Dialer{}
 		server := &Server{
-			dialer: dialer,
+			handler: newHandler(),
+			dialer:  dialer,
 			config: settings.Health{
-				TargetAddress: listeningAddress.String(),
+				Targets: []settings.HealthTarget{
+					{Type: constants.HealthProbeTCP, Address: listeningAddress.String()},
+				},
+				TargetsPolicy: constants.HealthPolicyAll,
 			},
 		}
 
@@ -65,6 +76,77 @@ func Test_Server_healthCheck(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("policy with failing targets", func(t *testing.T) {
+		t.Parallel()
+
+		httpServer := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		t.Cleanup(httpServer.Close)
+
+		targets := []settings.HealthTarget{
+			{Type: constants.HealthProbeHTTP, Address: httpServer.URL, ExpectedStatus: http.StatusNoContent},
+			{Type: constants.HealthProbeHTTP, Address: httpServer.URL, ExpectedStatus: http.StatusOK},
+			{Type: "unknown", Address: "x"},
+		}
+
+		testCases := map[string]struct {
+			policy     string
+			errWrapped error
+			errMessage string
+		}{
+			"any": {
+				policy: constants.HealthPolicyAny,
+			},
+			"one of three": {
+				policy: "1",
+			},
+			"two of three": {
+				policy:     "2",
+				errWrapped: ErrNotEnoughTargetsPassed,
+				errMessage: "not enough health targets passed: 1 of 3 passed and 2 required: " +
+					"http " + httpServer.URL + " (expected status 200): " +
+					"HTTP response status is unexpected: 204 instead of 200; " +
+					"unknown x: probe type is unknown: unknown",
+			},
+			"all": {
+				policy:     constants.HealthPolicyAll,
+				errWrapped: ErrNotEnoughTargetsPassed,
+				errMessage: "not enough health targets passed: 1 of 3 passed and 3 required: " +
+					"http " + httpServer.URL + " (expected status 200): " +
+					"HTTP response status is unexpected: 204 instead of 200; " +
+					"unknown x: probe type is unknown: unknown",
+			},
+		}
+
+		for name, testCase := range testCases {
+			testCase := testCase
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				server := &Server{
+					handler:    newHandler(),
+					httpClient: httpServer.Client(),
+					config: settings.Health{
+						Targets:       targets,
+						TargetsPolicy: testCase.policy,
+					},
+				}
+
+				err := server.healthCheck(context.Background())
+
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				if testCase.errWrapped != nil {
+					assert.EqualError(t, err, testCase.errMessage)
+				}
+				results := server.GetProbeResults()
+				require.Len(t, results, len(targets))
+				assert.NoError(t, results[0].Err)
+			})
+		}
+	})
 }
 
 func Test_makeAddressToDial(t *testing.T) {
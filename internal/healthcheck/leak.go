@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/publicip"
+)
+
+type leakCheck struct {
+	fetcher publicip.Fetcher
+	// hostIP is the public IP address of the host fetched
+	// before the VPN is up. The leak check is disabled if it is nil.
+	hostIP    net.IP
+	lastCheck time.Time
+	// err is the IP leak error found by the last leak check,
+	// and is nil if no leak was detected.
+	err error
+}
+
+// leakError checks for an IP leak if the check is due, and returns
+// the IP leak error found by the last leak check, so the program
+// stays unhealthy until a subsequent leak check passes.
+func (s *Server) leakError(ctx context.Context) (err error) {
+	if !s.leakCheckDue() {
+		return s.leak.err
+	}
+
+	err = s.checkLeak(ctx)
+	switch {
+	case errors.Is(err, ErrIPLeak):
+		s.logger.Error("CRITICAL: " + err.Error() +
+			", traffic is not going through the VPN!")
+	case err != nil:
+		s.logger.Info("cannot check for IP leak: " + err.Error())
+	}
+	return s.leak.err
+}
+
+// leakCheckDue returns true if the leak check is enabled,
+// the VPN is running and the leak check period elapsed.
+func (s *Server) leakCheckDue() bool {
+	period := *s.config.LeakCheckPeriod
+	return period > 0 && s.leak.hostIP != nil &&
+		time.Since(s.leak.lastCheck) >= period &&
+		s.vpn.looper.GetStatus() == constants.Running
+}
+
+// leakCheckAfterRecovery makes the leak check due if an IP leak
+// was detected, so the VPN recovered after waiting for the healthy
+// wait is checked again without waiting for the leak check period.
+func (s *Server) leakCheckAfterRecovery() {
+	if s.leak.err != nil {
+		s.leak.lastCheck = time.Time{}
+	}
+}
+
+var ErrIPLeak = errors.New("IP leak detected")
+
+// checkLeak fetches the public IP address seen through the VPN
+// and returns an error wrapping ErrIPLeak if it is the same as
+// the host public IP address. The IP leak error is recorded until
+// a subsequent check finds no leak.
+func (s *Server) checkLeak(ctx context.Context) (err error) {
+	s.leak.lastCheck = time.Now()
+
+	const timeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	publicIP, err := s.leak.fetcher.FetchPublicIP(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot fetch public IP address: %w", err)
+	}
+
+	if publicIP.Equal(s.leak.hostIP) {
+		s.leak.err = fmt.Errorf("%w: public IP address %s is the host public IP address",
+			ErrIPLeak, publicIP)
+		return s.leak.err
+	}
+
+	s.leak.err = nil
+	return nil
+}
@@ -0,0 +1,180 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testFetcher struct {
+	ip  net.IP
+	err error
+}
+
+func (f *testFetcher) FetchPublicIP(context.Context) (ip net.IP, err error) {
+	return f.ip, f.err
+}
+
+func Test_Server_checkLeak(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		fetcher    *testFetcher
+		errWrapped error
+		errMessage string
+		leaking    bool
+	}{
+		"fetch error": {
+			fetcher:    &testFetcher{err: errTest},
+			errWrapped: errTest,
+			errMessage: "cannot fetch public IP address: test error",
+		},
+		"different IP": {
+			fetcher: &testFetcher{ip: net.IPv4(2, 2, 2, 2)},
+		},
+		"same IP": {
+			fetcher:    &testFetcher{ip: net.IPv4(1, 1, 1, 1)},
+			errWrapped: ErrIPLeak,
+			errMessage: "IP leak detected: public IP address 1.1.1.1 " +
+				"is the host public IP address",
+			leaking: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &Server{
+				leak: leakCheck{
+					fetcher: testCase.fetcher,
+					hostIP:  net.IP{1, 1, 1, 1},
+				},
+			}
+
+			err := server.checkLeak(context.Background())
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.False(t, server.leak.lastCheck.IsZero())
+			assert.Equal(t, testCase.leaking, server.leak.err != nil)
+		})
+	}
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Info(s string)  { l.lines = append(l.lines, "INFO "+s) }
+func (l *testLogger) Error(s string) { l.lines = append(l.lines, "ERROR "+s) }
+
+func Test_Server_leakError(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	errLeak := errors.New("IP leak detected: public IP address 1.1.1.1 " +
+		"is the host public IP address")
+	durationPtr := func(d time.Duration) *time.Duration { return &d }
+
+	testCases := map[string]struct {
+		fetcher    *testFetcher
+		lastCheck  time.Time
+		previous   error
+		vpnStatus  models.LoopStatus
+		errMessage string
+		logs       []string
+	}{
+		"not due": {
+			fetcher:   &testFetcher{ip: net.IPv4(1, 1, 1, 1)},
+			lastCheck: time.Now(),
+			vpnStatus: constants.Running,
+		},
+		"leak kept until next check": {
+			fetcher:    &testFetcher{ip: net.IPv4(2, 2, 2, 2)},
+			lastCheck:  time.Now(),
+			previous:   errLeak,
+			vpnStatus:  constants.Running,
+			errMessage: errLeak.Error(),
+		},
+		"VPN not running": {
+			fetcher:   &testFetcher{ip: net.IPv4(1, 1, 1, 1)},
+			vpnStatus: constants.Stopped,
+		},
+		"leak detected": {
+			fetcher:    &testFetcher{ip: net.IPv4(1, 1, 1, 1)},
+			vpnStatus:  constants.Running,
+			errMessage: errLeak.Error(),
+			logs: []string{"ERROR CRITICAL: " + errLeak.Error() +
+				", traffic is not going through the VPN!"},
+		},
+		"leak resolved": {
+			fetcher:   &testFetcher{ip: net.IPv4(2, 2, 2, 2)},
+			previous:  errLeak,
+			vpnStatus: constants.Running,
+		},
+		"fetch error": {
+			fetcher:   &testFetcher{err: errTest},
+			vpnStatus: constants.Running,
+			logs: []string{"INFO cannot check for IP leak: " +
+				"cannot fetch public IP address: test error"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := &testLogger{}
+			server := &Server{
+				config: settings.Health{LeakCheckPeriod: durationPtr(time.Minute)},
+				logger: logger,
+				vpn: vpnHealth{
+					looper: &testVPNLooper{status: testCase.vpnStatus},
+				},
+				leak: leakCheck{
+					fetcher:   testCase.fetcher,
+					hostIP:    net.IP{1, 1, 1, 1},
+					lastCheck: testCase.lastCheck,
+					err:       testCase.previous,
+				},
+			}
+
+			err := server.leakError(context.Background())
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.logs, logger.lines)
+		})
+	}
+}
+
+func Test_Server_leakCheckAfterRecovery(t *testing.T) {
+	t.Parallel()
+
+	lastCheck := time.Now()
+	server := &Server{leak: leakCheck{lastCheck: lastCheck}}
+
+	server.leakCheckAfterRecovery()
+	assert.Equal(t, lastCheck, server.leak.lastCheck)
+
+	server.leak.err = ErrIPLeak
+	server.leakCheckAfterRecovery()
+	assert.True(t, server.leak.lastCheck.IsZero())
+}
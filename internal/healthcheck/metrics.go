@@ -0,0 +1,8 @@
+package healthcheck
+
+import "time"
+
+type Metrics interface {
+	HealthcheckDone(duration time.Duration, err error)
+	ProbeDone(probeType, address string, latency time.Duration, err error)
+}
@@ -2,9 +2,12 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/vpn"
 )
 
@@ -15,10 +18,60 @@ type vpnHealth struct {
 }
 
 func (s *Server) onUnhealthyVPN(ctx context.Context) {
-	s.logger.Info("program has been unhealthy for " +
-		s.vpn.healthyWait.String() + ": restarting VPN")
-	_, _ = s.vpn.looper.ApplyStatus(ctx, constants.Stopped)
-	_, _ = s.vpn.looper.ApplyStatus(ctx, constants.Running)
+	recovery := s.handler.getRecovery()
+	recovery.Attempts++
+	step := nextRecoveryStep(recovery, s.config.Recovery)
+
+	// Failing over to the next VPN profile takes precedence
+	// once the current profile has been failing for too long,
+	// and the recovery steps start over for the new profile.
+	outcome, err := s.vpn.looper.FailOver(ctx)
+	if err == nil {
+		step = RecoveryStepFailover
+		recovery = Recovery{Attempts: recovery.Attempts}
+	} else if !errors.Is(err, vpn.ErrFailoverNotDue) {
+		s.logger.Info("cannot fail over: " + err.Error())
+	}
+
+	s.logger.Info(fmt.Sprintf("program has been unhealthy for %s: recovery attempt %d: %s",
+		s.vpn.healthyWait, recovery.Attempts, step))
+
+	if step == RecoveryStepTCPFallback {
+		recovery.TCPFallback = true
+		outcome, err = s.vpn.looper.FallbackToTCP(ctx)
+		if err != nil {
+			s.logger.Info("cannot fall back to TCP: " + err.Error())
+			step = RecoveryStepSwitchServer
+		}
+	}
+
+	if step == RecoveryStepSwitchServer {
+		outcome, err = s.vpn.looper.SwitchServer(ctx, *s.config.Recovery.ExcludeDuration)
+		if err != nil {
+			s.logger.Info("cannot switch server: " + err.Error())
+			step = RecoveryStepRestart
+		} else {
+			recovery.ServerSwitches++
+		}
+	}
+
+	if step == RecoveryStepRestart {
+		_, _ = s.vpn.looper.ApplyStatus(ctx, constants.Stopped)
+		outcome, _ = s.vpn.looper.ApplyStatus(ctx, constants.Running)
+		recovery.Restarts++
+	}
+
+	recovery.LastStep = step
+	recovery.LastOutcome = outcome
+	recovery.LastTime = time.Now()
+	s.handler.setRecovery(recovery)
+	s.logger.Info("recovery step " + step + ": " + outcome)
+	s.publisher.Publish("healthcheck", events.TypeRecovery, events.RecoveryData{
+		Step:    step,
+		Attempt: recovery.Attempts,
+		Outcome: outcome,
+	})
+
 	s.vpn.healthyWait += *s.config.VPN.Addition
 	s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 }
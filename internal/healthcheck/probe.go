@@ -0,0 +1,174 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// ProbeResult is the result of probing a health check target.
+type ProbeResult struct {
+	Target  settings.HealthTarget
+	Latency time.Duration
+	// Err is the probe error, and is nil if the probe succeeded.
+	Err error
+}
+
+var ErrProbeTypeUnknown = errors.New("probe type is unknown")
+
+func (s *Server) probe(ctx context.Context, target settings.HealthTarget) (err error) {
+	switch target.Type {
+	case constants.HealthProbeTCP:
+		return s.probeTCP(ctx, target.Address)
+	case constants.HealthProbeHTTP:
+		return s.probeHTTP(ctx, target.Address, target.ExpectedStatus)
+	case constants.HealthProbeDNS:
+		return s.probeDNS(ctx, target.Address)
+	case constants.HealthProbeICMP:
+		return s.probeICMP(ctx, target.Address)
+	default:
+		return fmt.Errorf("%w: %s", ErrProbeTypeUnknown, target.Type)
+	}
+}
+
+func (s *Server) probeTCP(ctx context.Context, address string) (err error) {
+	address, err = makeAddressToDial(address)
+	if err != nil {
+		return err
+	}
+
+	const dialNetwork = "tcp4"
+	connection, err := s.dialer.DialContext(ctx, dialNetwork, address)
+	if err != nil {
+		return fmt.Errorf("cannot dial: %w", err)
+	}
+
+	err = connection.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close connection: %w", err)
+	}
+
+	return nil
+}
+
+var ErrHTTPStatusUnexpected = errors.New("HTTP response status is unexpected")
+
+func (s *Server) probeHTTP(ctx context.Context, url string,
+	expectedStatus int) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close response body: %w", err)
+	}
+
+	if response.StatusCode != expectedStatus {
+		return fmt.Errorf("%w: %d instead of %d",
+			ErrHTTPStatusUnexpected, response.StatusCode, expectedStatus)
+	}
+
+	return nil
+}
+
+var ErrNoAddressResolved = errors.New("no address resolved")
+
+func (s *Server) probeDNS(ctx context.Context, hostname string) (err error) {
+	addresses, err := s.resolver.LookupHost(ctx, hostname)
+	if err != nil {
+		return fmt.Errorf("cannot resolve: %w", err)
+	} else if len(addresses) == 0 {
+		return fmt.Errorf("%w: for %s", ErrNoAddressResolved, hostname)
+	}
+	return nil
+}
+
+// probeICMP sends an ICMP echo request to the host and waits for
+// the matching echo reply. It requires the NET_RAW capability.
+func (s *Server) probeICMP(ctx context.Context, host string) (err error) {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ips, err := s.resolver.LookupIP(ctx, "ip4", host)
+		if err != nil {
+			return fmt.Errorf("cannot resolve: %w", err)
+		} else if len(ips) == 0 {
+			return fmt.Errorf("%w: for %s", ErrNoAddressResolved, host)
+		}
+		ip = ips[0]
+	}
+
+	connection, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return fmt.Errorf("cannot listen for ICMP packets: %w", err)
+	}
+	defer connection.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		const defaultTimeout = 3 * time.Second
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	err = connection.SetDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("cannot set deadline: %w", err)
+	}
+
+	const idMask = 0xffff
+	echo := &icmp.Echo{
+		ID:   os.Getpid() & idMask,
+		Seq:  int(atomic.AddUint32(&s.icmpSequence, 1) & idMask),
+		Data: []byte("gluetun"),
+	}
+	request := icmp.Message{Type: ipv4.ICMPTypeEcho, Body: echo}
+	requestBytes, err := request.Marshal(nil)
+	if err != nil {
+		return fmt.Errorf("cannot encode ICMP echo request: %w", err)
+	}
+
+	_, err = connection.WriteTo(requestBytes, &net.IPAddr{IP: ip})
+	if err != nil {
+		return fmt.Errorf("cannot send ICMP echo request: %w", err)
+	}
+
+	const maxPacketSize = 1500
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, peer, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return fmt.Errorf("cannot receive ICMP echo reply: %w", err)
+		}
+
+		const icmpProtocolNumber = 1
+		reply, err := icmp.ParseMessage(icmpProtocolNumber, buffer[:n])
+		if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+
+		replyEcho, ok := reply.Body.(*icmp.Echo)
+		if !ok || replyEcho.ID != echo.ID || replyEcho.Seq != echo.Seq {
+			continue // reply to another ICMP echo request
+		}
+
+		peerIPAddress, ok := peer.(*net.IPAddr)
+		if ok && peerIPAddress.IP.Equal(ip) {
+			return nil
+		}
+	}
+}
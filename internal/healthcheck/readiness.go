@@ -0,0 +1,111 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// ServeHTTP serves the liveness endpoint /livez, the readiness
+// endpoint /readyz and the health check on any other path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/livez":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not supported for liveness", http.StatusBadRequest)
+			return
+		}
+		s.serveLiveness(w)
+	case "/readyz":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not supported for readiness", http.StatusBadRequest)
+			return
+		}
+		s.serveReadiness(w)
+	default:
+		s.handler.ServeHTTP(w, r)
+	}
+}
+
+func (s *Server) serveLiveness(w http.ResponseWriter) {
+	err := s.checkLiveness(time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+var ErrHealthLoopStuck = errors.New("health check loop is stuck")
+
+// checkLiveness returns an error if the health check loop did not
+// iterate for too long, in which case the process should be restarted.
+// The VPN status is only reported by the readiness endpoint, since a
+// crashed VPN loop is recovered by the program and restarting the
+// process would not fix its configuration or the network.
+func (s *Server) checkLiveness(now time.Time) (err error) {
+	// An iteration can take a while if the VPN is restarted,
+	// so only consider the loop stuck after a few minutes.
+	const maxLoopSilence = 3 * time.Minute
+	loopBeat := s.handler.getLoopBeat()
+	if !loopBeat.IsZero() && now.Sub(loopBeat) > maxLoopSilence {
+		return fmt.Errorf("%w: last iteration started %s ago",
+			ErrHealthLoopStuck, now.Sub(loopBeat).Round(time.Second))
+	}
+
+	return nil
+}
+
+type readiness struct {
+	Ready bool `json:"ready"`
+	// Reasons maps each failing subsystem to the
+	// reason it is not ready.
+	Reasons map[string]string `json:"reasons,omitempty"`
+}
+
+func (s *Server) serveReadiness(w http.ResponseWriter) {
+	reasons := s.readinessReasons()
+	data := readiness{
+		Ready:   len(reasons) == 0,
+		Reasons: reasons,
+	}
+
+	status := http.StatusOK
+	if !data.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(data)
+	if err != nil {
+		s.logger.Error("cannot encode readiness: " + err.Error())
+	}
+}
+
+// readinessReasons returns the reason each subsystem is not ready,
+// or an empty map if the tunnel is up, the DNS is ready and the
+// health check is passing.
+func (s *Server) readinessReasons() (reasons map[string]string) {
+	reasons = make(map[string]string)
+
+	if status := s.vpn.looper.GetStatus(); status != constants.Running {
+		reasons["vpn"] = "VPN status is " + string(status)
+	}
+
+	if *s.dnsLooper.GetSettings().DoT.Enabled {
+		if status := s.dnsLooper.GetStatus(); status != constants.Running {
+			reasons["dns"] = "DNS over TLS status is " + string(status)
+		}
+	}
+
+	if err := s.handler.getErr(); err != nil {
+		reasons["healthcheck"] = err.Error()
+	}
+
+	return reasons
+}
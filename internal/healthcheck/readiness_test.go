@@ -0,0 +1,142 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/vpn"
+	"github.com/stretchr/testify/assert"
+)
+
+type testVPNLooper struct {
+	vpn.Looper
+	status models.LoopStatus
+}
+
+func (l *testVPNLooper) GetStatus() models.LoopStatus { return l.status }
+
+type testDNSLooper struct {
+	dns.Looper
+	status     models.LoopStatus
+	dotEnabled bool
+}
+
+func (l *testDNSLooper) GetStatus() models.LoopStatus { return l.status }
+
+func (l *testDNSLooper) GetSettings() (settings settings.DNS) {
+	settings.DoT.Enabled = &l.dotEnabled
+	return settings
+}
+
+func Test_Server_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	testCases := map[string]struct {
+		path       string
+		loopBeat   time.Time
+		healthErr  error
+		vpnStatus  models.LoopStatus
+		dnsStatus  models.LoopStatus
+		dotEnabled bool
+		status     int
+		body       string
+	}{
+		"live": {
+			path:      "/livez",
+			loopBeat:  now,
+			vpnStatus: constants.Starting,
+			status:    http.StatusOK,
+		},
+		"health loop stuck": {
+			path:      "/livez",
+			loopBeat:  now.Add(-time.Hour),
+			vpnStatus: constants.Running,
+			status:    http.StatusServiceUnavailable,
+			body:      "health check loop is stuck: last iteration started 1h0m0s ago\n",
+		},
+		"live with VPN loop crashed": {
+			path:      "/livez",
+			loopBeat:  now,
+			vpnStatus: constants.Crashed,
+			status:    http.StatusOK,
+		},
+		"not ready with VPN loop crashed": {
+			path:      "/readyz",
+			vpnStatus: constants.Crashed,
+			dnsStatus: constants.Running,
+			status:    http.StatusServiceUnavailable,
+			body: `{"ready":false,"reasons":{` +
+				`"vpn":"VPN status is crashed"}}` + "\n",
+		},
+		"ready": {
+			path:       "/readyz",
+			vpnStatus:  constants.Running,
+			dnsStatus:  constants.Running,
+			dotEnabled: true,
+			status:     http.StatusOK,
+			body:       `{"ready":true}` + "\n",
+		},
+		"not ready": {
+			path:       "/readyz",
+			healthErr:  errors.New("test error"),
+			vpnStatus:  constants.Starting,
+			dnsStatus:  constants.Crashed,
+			dotEnabled: true,
+			status:     http.StatusServiceUnavailable,
+			body: `{"ready":false,"reasons":{` +
+				`"dns":"DNS over TLS status is crashed",` +
+				`"healthcheck":"test error",` +
+				`"vpn":"VPN status is starting"}}` + "\n",
+		},
+		"ready without DNS over TLS": {
+			path:      "/readyz",
+			vpnStatus: constants.Running,
+			dnsStatus: constants.Stopped,
+			status:    http.StatusOK,
+			body:      `{"ready":true}` + "\n",
+		},
+		"health check": {
+			path:      "/",
+			healthErr: errors.New("test error"),
+			status:    http.StatusInternalServerError,
+			body:      "test error\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &Server{
+				handler: newHandler(),
+				vpn: vpnHealth{
+					looper: &testVPNLooper{status: testCase.vpnStatus},
+				},
+				dnsLooper: &testDNSLooper{
+					status:     testCase.dnsStatus,
+					dotEnabled: testCase.dotEnabled,
+				},
+			}
+			server.handler.setErr(testCase.healthErr)
+			server.handler.setLoopBeat(testCase.loopBeat)
+
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			recorder := httptest.NewRecorder()
+
+			server.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.body, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+const (
+	RecoveryStepRestart      = "restart"
+	RecoveryStepSwitchServer = "switch-server"
+	RecoveryStepTCPFallback  = "tcp-fallback"
+	RecoveryStepFailover     = "failover"
+)
+
+// Recovery is the state of the recovery actions taken
+// since the program was last healthy.
+type Recovery struct {
+	// Attempts is the number of recovery attempts.
+	Attempts uint
+	// Restarts is the number of VPN restarts done.
+	Restarts uint
+	// ServerSwitches is the number of server switches done.
+	ServerSwitches uint
+	// TCPFallback is true if the fall back from UDP
+	// to TCP was attempted.
+	TCPFallback bool
+	// LastStep is the last recovery step taken, and
+	// is empty if no recovery attempt was made.
+	LastStep string
+	// LastOutcome is the outcome of the last recovery step.
+	LastOutcome string
+	// LastTime is the time the last recovery step was taken at.
+	LastTime time.Time
+}
+
+// nextRecoveryStep returns the next recovery step to take, which
+// is to restart the VPN a number of times, then switch to other
+// servers a number of times, then optionally fall back to TCP and
+// finally keep on switching servers.
+func nextRecoveryStep(recovery Recovery,
+	settings settings.HealthRecovery) (step string) {
+	switch {
+	case recovery.Restarts < uint(*settings.Restarts):
+		return RecoveryStepRestart
+	case recovery.ServerSwitches < uint(*settings.ServerSwitches):
+		return RecoveryStepSwitchServer
+	case *settings.TCPFallback && !recovery.TCPFallback:
+		return RecoveryStepTCPFallback
+	case *settings.ServerSwitches == 0:
+		return RecoveryStepRestart
+	default:
+		return RecoveryStepSwitchServer
+	}
+}
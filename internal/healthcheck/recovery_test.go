@@ -0,0 +1,72 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nextRecoveryStep(t *testing.T) {
+	t.Parallel()
+
+	newSettings := func(restarts, serverSwitches uint8,
+		tcpFallback bool) settings.HealthRecovery {
+		return settings.HealthRecovery{
+			Restarts:       &restarts,
+			ServerSwitches: &serverSwitches,
+			TCPFallback:    &tcpFallback,
+		}
+	}
+
+	testCases := map[string]struct {
+		recovery Recovery
+		settings settings.HealthRecovery
+		step     string
+	}{
+		"first restart": {
+			settings: newSettings(2, 2, true),
+			step:     RecoveryStepRestart,
+		},
+		"restarts exhausted": {
+			recovery: Recovery{Restarts: 2},
+			settings: newSettings(2, 2, true),
+			step:     RecoveryStepSwitchServer,
+		},
+		"server switches exhausted": {
+			recovery: Recovery{Restarts: 2, ServerSwitches: 2},
+			settings: newSettings(2, 2, true),
+			step:     RecoveryStepTCPFallback,
+		},
+		"TCP fallback done": {
+			recovery: Recovery{Restarts: 2, ServerSwitches: 2, TCPFallback: true},
+			settings: newSettings(2, 2, true),
+			step:     RecoveryStepSwitchServer,
+		},
+		"TCP fallback disabled": {
+			recovery: Recovery{Restarts: 2, ServerSwitches: 2},
+			settings: newSettings(2, 2, false),
+			step:     RecoveryStepSwitchServer,
+		},
+		"no restart": {
+			settings: newSettings(0, 1, false),
+			step:     RecoveryStepSwitchServer,
+		},
+		"no server switch": {
+			recovery: Recovery{Restarts: 5},
+			settings: newSettings(2, 0, false),
+			step:     RecoveryStepRestart,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			step := nextRecoveryStep(testCase.recovery, testCase.settings)
+
+			assert.Equal(t, testCase.step, step)
+		})
+	}
+}
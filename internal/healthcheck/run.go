@@ -5,6 +5,8 @@ import (
 	"errors"
 	"net/http"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
 )
 
 func (s *Server) Run(ctx context.Context, done chan<- struct{}) {
@@ -13,10 +15,7 @@ func (s *Server) Run(ctx context.Context, done chan<- struct{}) {
 	loopDone := make(chan struct{})
 	go s.runHealthcheckLoop(ctx, loopDone)
 
-	server := http.Server{
-		Addr:    s.config.ServerAddress,
-		Handler: s.handler,
-	}
+	server := http.Server{Handler: s}
 	serverDone := make(chan struct{})
 	go func() {
 		defer close(serverDone)
@@ -29,10 +28,15 @@ func (s *Server) Run(ctx context.Context, done chan<- struct{}) {
 		}
 	}()
 
-	s.logger.Info("listening on " + s.config.ServerAddress)
-	err := server.ListenAndServe()
-	if err != nil && !errors.Is(ctx.Err(), context.Canceled) {
+	listener, err := httpserver.Listen(s.config.ServerAddress, s.config.ServerTLS)
+	if err != nil {
 		s.logger.Error(err.Error())
+	} else {
+		s.logger.Info("listening on " + s.config.ServerAddress)
+		err = server.Serve(listener)
+		if err != nil && !errors.Is(ctx.Err(), context.Canceled) {
+			s.logger.Error(err.Error())
+		}
 	}
 
 	<-loopDone
@@ -3,8 +3,12 @@ package healthcheck
 import (
 	"context"
 	"net"
+	"net/http"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/publicip"
 	"github.com/qdm12/gluetun/internal/vpn"
 )
 
@@ -15,23 +19,74 @@ type ServerRunner interface {
 }
 
 type Server struct {
-	logger  Logger
-	handler *handler
-	dialer  *net.Dialer
-	config  settings.Health
-	vpn     vpnHealth
+	logger       Logger
+	handler      *handler
+	dialer       *net.Dialer
+	httpClient   *http.Client
+	resolver     *net.Resolver
+	icmpSequence uint32
+	config       settings.Health
+	vpn          vpnHealth
+	dnsLooper    dns.Looper
+	leak         leakCheck
+	wireguard    wireguardHandshake
+	publisher    events.Publisher
+	metrics      Metrics
 }
 
 func NewServer(config settings.Health,
-	logger Logger, vpnLooper vpn.Looper) *Server {
+	logger Logger, vpnLooper vpn.Looper, dnsLooper dns.Looper, publisher events.Publisher,
+	metrics Metrics, ipFetcher publicip.Fetcher, hostPublicIP net.IP) *Server {
 	return &Server{
 		logger:  logger,
 		handler: newHandler(),
 		dialer:  &net.Dialer{},
-		config:  config,
+		httpClient: &http.Client{
+			// Do not follow redirects so a redirect
+			// status code can be expected.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		resolver:  &net.Resolver{},
+		config:    config,
+		dnsLooper: dnsLooper,
+		publisher: publisher,
+		metrics:   metrics,
 		vpn: vpnHealth{
 			looper:      vpnLooper,
 			healthyWait: *config.VPN.Initial,
 		},
+		leak: leakCheck{
+			fetcher: ipFetcher,
+			hostIP:  hostPublicIP,
+		},
+		wireguard: wireguardHandshake{
+			getDevice: getWireguardDevice,
+		},
 	}
 }
+
+type HealthGetter interface {
+	GetHealth() (err error)
+	GetProbeResults() (results []ProbeResult)
+	GetRecovery() (recovery Recovery)
+}
+
+// GetHealth returns the error of the last health check,
+// or nil if it succeeded.
+func (s *Server) GetHealth() (err error) {
+	return s.handler.getErr()
+}
+
+// GetProbeResults returns the result of each target probe
+// of the last health check.
+func (s *Server) GetProbeResults() (results []ProbeResult) {
+	return s.handler.getResults()
+}
+
+// GetRecovery returns the state of the recovery actions
+// taken since the program was last healthy.
+func (s *Server) GetRecovery() (recovery Recovery) {
+	return s.handler.getRecovery()
+}
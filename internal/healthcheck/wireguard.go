@@ -0,0 +1,119 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+type wireguardHandshake struct {
+	getDevice func(name string) (device *wgtypes.Device, err error)
+	// connection is the VPN connection the state below is for.
+	connection models.Connection
+	// noHandshakeSince is the time the device was first seen
+	// without any handshake, and is zero otherwise.
+	noHandshakeSince time.Time
+	// blocked is true if the server was blocked for
+	// never completing a handshake.
+	blocked bool
+}
+
+func getWireguardDevice(name string) (device *wgtypes.Device, err error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return nil, fmt.Errorf("cannot open wgctrl: %w", err)
+	}
+	defer client.Close()
+
+	device, err = client.Device(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get device %s: %w", name, err)
+	}
+	return device, nil
+}
+
+// checkWireguardHandshake returns an error if the VPN is running
+// using Wireguard and its latest handshake is older than the
+// handshake timeout.
+func (s *Server) checkWireguardHandshake() (err error) {
+	timeout := *s.config.WireguardHandshakeTimeout
+	if timeout == 0 || s.vpn.looper.GetStatus() != constants.Running {
+		s.wireguard.reset(models.Connection{})
+		return nil
+	}
+
+	connection, vpnInterface := s.vpn.looper.GetConnection()
+	if connection.Type != constants.Wireguard || vpnInterface == "" {
+		return nil
+	} else if !connection.Equal(s.wireguard.connection) {
+		s.wireguard.reset(connection)
+	}
+
+	device, err := s.wireguard.getDevice(vpnInterface)
+	if err != nil {
+		return fmt.Errorf("cannot check Wireguard handshake: %w", err)
+	}
+
+	err = s.wireguard.check(device, timeout, time.Now())
+	if errors.Is(err, ErrWireguardNoHandshake) && !s.wireguard.blocked {
+		s.wireguard.blocked = true
+		blockErr := s.vpn.looper.BlockCurrentServer(err.Error())
+		if blockErr != nil {
+			s.logger.Error("cannot block server: " + blockErr.Error())
+		}
+	}
+	return err
+}
+
+func (w *wireguardHandshake) reset(connection models.Connection) {
+	w.connection = connection
+	w.noHandshakeSince = time.Time{}
+	w.blocked = false
+}
+
+var (
+	ErrWireguardHandshakeTooOld = errors.New("latest Wireguard handshake is too old")
+	ErrWireguardNoHandshake     = errors.New("no Wireguard handshake completed")
+)
+
+func (w *wireguardHandshake) check(device *wgtypes.Device,
+	timeout time.Duration, now time.Time) (err error) {
+	var lastHandshake time.Time
+	var received, sent int64
+	for _, peer := range device.Peers {
+		if peer.LastHandshakeTime.After(lastHandshake) {
+			lastHandshake = peer.LastHandshakeTime
+		}
+		received += peer.ReceiveBytes
+		sent += peer.TransmitBytes
+	}
+
+	neverHandshaked := lastHandshake.IsZero()
+	if !neverHandshaked {
+		w.noHandshakeSince = time.Time{}
+	} else {
+		// No handshake happened yet, so measure the time
+		// elapsed since the device was first seen.
+		if w.noHandshakeSince.IsZero() {
+			w.noHandshakeSince = now
+		}
+		lastHandshake = w.noHandshakeSince
+	}
+
+	age := now.Sub(lastHandshake)
+	if age <= timeout {
+		return nil
+	}
+
+	if neverHandshaked {
+		return fmt.Errorf("%w after %s (%d bytes received, %d bytes sent)",
+			ErrWireguardNoHandshake, age.Round(time.Second), received, sent)
+	}
+	return fmt.Errorf("%w: no handshake for %s (%d bytes received, %d bytes sent)",
+		ErrWireguardHandshakeTooOld, age.Round(time.Second), received, sent)
+}
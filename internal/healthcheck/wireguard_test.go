@@ -0,0 +1,74 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func Test_wireguardHandshake_check(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	const timeout = 3 * time.Minute
+
+	testCases := map[string]struct {
+		handshake        wireguardHandshake
+		device           *wgtypes.Device
+		errWrapped       error
+		errMessage       string
+		noHandshakeSince time.Time
+	}{
+		"recent handshake": {
+			handshake: wireguardHandshake{noHandshakeSince: now.Add(-time.Hour)},
+			device: &wgtypes.Device{
+				Peers: []wgtypes.Peer{
+					{LastHandshakeTime: now.Add(-time.Minute)},
+				},
+			},
+		},
+		"old handshake": {
+			device: &wgtypes.Device{
+				Peers: []wgtypes.Peer{{
+					LastHandshakeTime: now.Add(-4*time.Minute - 12*time.Second),
+					ReceiveBytes:      100,
+					TransmitBytes:     200,
+				}},
+			},
+			errWrapped: ErrWireguardHandshakeTooOld,
+			errMessage: "latest Wireguard handshake is too old: " +
+				"no handshake for 4m12s (100 bytes received, 200 bytes sent)",
+		},
+		"no handshake yet": {
+			device:           &wgtypes.Device{Peers: []wgtypes.Peer{{}}},
+			noHandshakeSince: now,
+		},
+		"no handshake for too long": {
+			handshake:  wireguardHandshake{noHandshakeSince: now.Add(-5 * time.Minute)},
+			device:     &wgtypes.Device{Peers: []wgtypes.Peer{{}}},
+			errWrapped: ErrWireguardNoHandshake,
+			errMessage: "no Wireguard handshake completed after 5m0s " +
+				"(0 bytes received, 0 bytes sent)",
+			noHandshakeSince: now.Add(-5 * time.Minute),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handshake := testCase.handshake
+
+			err := handshake.check(testCase.device, timeout, now)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.noHandshakeSince, handshake.noHandshakeSince)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package hooks runs user commands when events happen,
+// such as the VPN tunnel going up or the public IP changing.
+package hooks
+
+import (
+	"context"
+	"net"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/publicip/models"
+)
+
+const (
+	tunnelUp        = "tunnel-up"
+	tunnelDown      = "tunnel-down"
+	portForwarded   = "port-forwarded"
+	publicIPChanged = "public-ip-changed"
+)
+
+// Runner runs the hook commands for the events received.
+type Runner struct {
+	settings settings.Hooks
+	logger   Logger
+
+	// State built from the events received,
+	// passed to the hook commands.
+	tunnel   events.TunnelData
+	tunnelUp bool
+	publicIP net.IP
+	port     uint16
+}
+
+type Logger interface {
+	Info(s string)
+	Error(s string)
+}
+
+func New(settings settings.Hooks, logger Logger) *Runner {
+	return &Runner{
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Run runs the hook commands for the events received from the
+// subscriber until the context is canceled. The ready channel
+// is closed once subscribed to events. Hook commands are run one
+// at a time, in the order their events are received. Events
+// relevant to hooks are queued as soon as they are received,
+// so they are not dropped while a hook command is running.
+func (r *Runner) Run(ctx context.Context, subscriber events.Subscriber,
+	ready, done chan<- struct{}) {
+	defer close(done)
+
+	eventsCh, unsubscribe := subscriber.Subscribe()
+	defer unsubscribe()
+	close(ready)
+
+	queue := newQueue()
+	processDone := make(chan struct{})
+	go func() {
+		defer close(processDone)
+		for {
+			event, ok := queue.pop(ctx)
+			if !ok {
+				return
+			}
+			r.processEvent(ctx, event)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-processDone
+			return
+		case event := <-eventsCh:
+			if isHookEvent(event.Type) {
+				queue.push(event)
+			}
+		}
+	}
+}
+
+func isHookEvent(eventType events.Type) bool {
+	switch eventType {
+	case events.TypeTunnelUp, events.TypeTunnelDown,
+		events.TypePublicIP, events.TypePortForwarded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Runner) processEvent(ctx context.Context, event events.Event) {
+	switch event.Type {
+	case events.TypeTunnelUp:
+		tunnel, ok := event.Data.(events.TunnelData)
+		if !ok {
+			return
+		}
+		r.tunnel = tunnel
+		r.tunnelUp = true
+		r.runHook(ctx, tunnelUp, *r.settings.TunnelUp)
+	case events.TypeTunnelDown:
+		tunnel, ok := event.Data.(events.TunnelData)
+		if !ok || !r.tunnelUp {
+			return
+		}
+		r.tunnel = tunnel
+		r.tunnelUp = false
+		r.runHook(ctx, tunnelDown, *r.settings.TunnelDown)
+	case events.TypePublicIP:
+		data, ok := event.Data.(models.IPInfoData)
+		if !ok {
+			return
+		}
+		r.publicIP = data.IP
+		if r.publicIP == nil { // public IP data cleared
+			return
+		}
+		r.runHook(ctx, publicIPChanged, *r.settings.PublicIPChanged)
+	case events.TypePortForwarded:
+		port, ok := event.Data.(uint16)
+		if !ok {
+			return
+		}
+		r.port = port
+		if port == 0 { // port forwarding stopped
+			return
+		}
+		r.runHook(ctx, portForwarded, *r.settings.PortForwarded)
+	}
+}
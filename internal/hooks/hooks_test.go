@@ -0,0 +1,189 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/publicip/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLogger struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (t *testLogger) Info(s string)  { t.log("INFO " + s) }
+func (t *testLogger) Warn(s string)  { t.log("WARN " + s) }
+func (t *testLogger) Error(s string) { t.log("ERROR " + s) }
+
+func (t *testLogger) log(s string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lines = append(t.lines, s)
+}
+
+func newTestSettings(timeout time.Duration) settings.Hooks {
+	hooks := settings.Hooks{
+		TunnelUp: stringPtr(`echo "$GLUETUN_EVENT $GLUETUN_VPN_TYPE ` +
+			`$GLUETUN_VPN_INTERFACE $GLUETUN_VPN_SERVER $GLUETUN_VPN_SERVER_IP"`),
+		TunnelDown:      stringPtr(`echo "$GLUETUN_EVENT" >&2; exit 1`),
+		PortForwarded:   stringPtr(`echo "$GLUETUN_EVENT $GLUETUN_FORWARDED_PORT"`),
+		PublicIPChanged: stringPtr(`echo "$GLUETUN_EVENT $GLUETUN_PUBLIC_IP home=$HOME"`),
+		Timeout:         &timeout,
+	}
+	return hooks
+}
+
+func stringPtr(s string) *string { return &s }
+
+func Test_Runner_processEvent(t *testing.T) {
+	t.Parallel()
+
+	tunnel := events.TunnelData{
+		VPNType:    "wireguard",
+		Interface:  "wg0",
+		ServerIP:   net.IPv4(1, 2, 3, 4),
+		ServerName: "",
+	}
+
+	testCases := map[string]struct {
+		events []events.Event
+		lines  []string
+	}{
+		"tunnel up": {
+			events: []events.Event{
+				{Type: events.TypeTunnelUp, Data: tunnel},
+			},
+			lines: []string{
+				"INFO running tunnel-up hook",
+				"INFO tunnel-up: tunnel-up wireguard wg0 1.2.3.4 1.2.3.4",
+				"INFO tunnel-up hook completed",
+			},
+		},
+		"tunnel down without tunnel up": {
+			events: []events.Event{
+				{Type: events.TypeTunnelDown, Data: tunnel},
+			},
+		},
+		"failing tunnel down": {
+			events: []events.Event{
+				{Type: events.TypeTunnelUp, Data: events.TunnelData{}},
+				{Type: events.TypeTunnelDown, Data: tunnel},
+			},
+			lines: []string{
+				"INFO running tunnel-up hook",
+				"INFO tunnel-up: tunnel-up    ",
+				"INFO tunnel-up hook completed",
+				"INFO running tunnel-down hook",
+				"ERROR tunnel-down: tunnel-down",
+				"ERROR tunnel-down hook failed: exit status 1",
+			},
+		},
+		"public IP and port forwarded": {
+			events: []events.Event{
+				{Type: events.TypePublicIP, Data: models.IPInfoData{IP: net.IPv4(5, 6, 7, 8)}},
+				{Type: events.TypePortForwarded, Data: uint16(0)},
+				{Type: events.TypePortForwarded, Data: uint16(5678)},
+				{Type: events.TypePublicIP, Data: models.IPInfoData{}},
+			},
+			lines: []string{
+				"INFO running public-ip-changed hook",
+				"INFO public-ip-changed: public-ip-changed 5.6.7.8 home=",
+				"INFO public-ip-changed hook completed",
+				"INFO running port-forwarded hook",
+				"INFO port-forwarded: port-forwarded 5678",
+				"INFO port-forwarded hook completed",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := &testLogger{}
+			runner := New(newTestSettings(time.Minute), logger)
+
+			for _, event := range testCase.events {
+				runner.processEvent(context.Background(), event)
+			}
+
+			assert.Equal(t, testCase.lines, logger.lines)
+		})
+	}
+}
+
+func Test_Runner_timeout(t *testing.T) {
+	t.Parallel()
+
+	logger := &testLogger{}
+	hooks := newTestSettings(100 * time.Millisecond)
+	hooks.PortForwarded = stringPtr("echo started; sleep 10 & sleep 10")
+	runner := New(hooks, logger)
+
+	start := time.Now()
+	runner.processEvent(context.Background(), events.Event{
+		Type: events.TypePortForwarded,
+		Data: uint16(1),
+	})
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	expectedLines := []string{
+		"INFO running port-forwarded hook",
+		"INFO port-forwarded: started",
+		"ERROR port-forwarded hook failed: command killed: context deadline exceeded",
+	}
+	assert.Equal(t, expectedLines, logger.lines)
+}
+
+func Test_Runner_Run(t *testing.T) {
+	t.Parallel()
+
+	logger := &testLogger{}
+	hooks := newTestSettings(time.Minute)
+	hooks.PortForwarded = stringPtr(`sleep 0.02; echo "$GLUETUN_EVENT $GLUETUN_FORWARDED_PORT"`)
+	runner := New(hooks, logger)
+
+	brokerLogger := &testLogger{}
+	broker := events.New(brokerLogger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go runner.Run(ctx, broker, ready, done)
+	<-ready
+
+	// Publish more events than the broker subscriber buffer size,
+	// faster than the hook commands can run.
+	const portsCount = 40
+	for port := uint16(1); port <= portsCount; port++ {
+		broker.Publish("vpn", events.TypeStatus, "running")
+		broker.Publish("portforward", events.TypePortForwarded, port)
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool {
+		logger.mutex.Lock()
+		defer logger.mutex.Unlock()
+		return len(logger.lines) == 3*portsCount
+	}, 10*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+
+	assert.Empty(t, brokerLogger.lines)
+	require.Len(t, logger.lines, 3*portsCount)
+	for i := 0; i < portsCount; i++ {
+		assert.Equal(t, fmt.Sprintf("INFO port-forwarded: port-forwarded %d", i+1),
+			logger.lines[3*i+1])
+	}
+}
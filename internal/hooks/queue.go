@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/events"
+)
+
+// queue is an unbounded first in first out queue of events,
+// so events are not dropped while a hook command is running.
+type queue struct {
+	events []events.Event
+	mutex  sync.Mutex
+	// pushed is signaled when an event is pushed.
+	pushed chan struct{}
+}
+
+func newQueue() *queue {
+	return &queue{
+		pushed: make(chan struct{}, 1),
+	}
+}
+
+func (q *queue) push(event events.Event) {
+	q.mutex.Lock()
+	q.events = append(q.events, event)
+	q.mutex.Unlock()
+
+	select {
+	case q.pushed <- struct{}{}:
+	default: // already signaled
+	}
+}
+
+// pop blocks until an event is available or the context is
+// canceled, in which case it returns false.
+func (q *queue) pop(ctx context.Context) (event events.Event, ok bool) {
+	for {
+		q.mutex.Lock()
+		if len(q.events) > 0 {
+			event = q.events[0]
+			q.events[0] = events.Event{} // release the event data
+			q.events = q.events[1:]
+			q.mutex.Unlock()
+			return event, true
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return event, false
+		case <-q.pushed:
+		}
+	}
+}
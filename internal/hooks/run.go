@@ -0,0 +1,160 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func (r *Runner) runHook(ctx context.Context, event, command string) {
+	if command == "" {
+		return
+	}
+
+	r.logger.Info("running " + event + " hook")
+	err := r.runCommand(ctx, event, command)
+	if err != nil {
+		r.logger.Error(event + " hook failed: " + err.Error())
+		return
+	}
+	r.logger.Info(event + " hook completed")
+}
+
+// runCommand runs the command with the shell and logs its output.
+// The command and the processes it started are killed if the command
+// runs for longer than the hooks timeout.
+func (r *Runner) runCommand(ctx context.Context, event, command string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, *r.settings.Timeout)
+	defer cancel()
+
+	cmd := exec.Command("/bin/sh", "-c", command) // #nosec G204
+	cmd.Env = r.environment(event)
+	// Run the command in its own process group, so the shell
+	// and the processes it started can be killed together.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	stdout, err := r.streamOutput(event, r.logger.Info)
+	if err != nil {
+		return err
+	}
+	defer stdout.close()
+	cmd.Stdout = stdout.writer
+
+	stderr, err := r.streamOutput(event, r.logger.Error)
+	if err != nil {
+		return err
+	}
+	defer stderr.close()
+	cmd.Stderr = stderr.writer
+
+	err = cmd.Start()
+	// Close the writers of this process, so the streams end once
+	// the command and the processes it started exit.
+	_ = stdout.writer.Close()
+	_ = stderr.writer.Close()
+	if err != nil {
+		return err
+	}
+
+	waitError := make(chan error)
+	go func() {
+		waitError <- cmd.Wait()
+	}()
+
+	select {
+	case err = <-waitError:
+	case <-ctx.Done():
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-waitError
+		err = fmt.Errorf("command killed: %w", ctx.Err())
+	}
+
+	// Background processes started by the command can keep the
+	// output streams open after the command exits, so only wait
+	// for the output until the timeout.
+	select {
+	case <-stdout.done:
+	case <-ctx.Done():
+	}
+	select {
+	case <-stderr.done:
+	case <-ctx.Done():
+	}
+
+	return err
+}
+
+type outputStream struct {
+	reader *os.File
+	writer *os.File
+	done   <-chan struct{}
+}
+
+// streamOutput returns an output stream whose lines written
+// are logged with the log function given.
+func (r *Runner) streamOutput(event string, log func(s string)) (
+	stream outputStream, err error) {
+	stream.reader, stream.writer, err = os.Pipe()
+	if err != nil {
+		return stream, fmt.Errorf("cannot create output pipe: %w", err)
+	}
+
+	done := make(chan struct{})
+	stream.done = done
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(stream.reader)
+		for scanner.Scan() {
+			log(event + ": " + scanner.Text())
+		}
+	}()
+
+	return stream, nil
+}
+
+// close closes the reader and writer of the stream, and waits
+// for its lines to be logged.
+func (s outputStream) close() {
+	_ = s.writer.Close()
+	_ = s.reader.Close()
+	<-s.done
+}
+
+// environment returns the environment variables for the
+// hook command. The environment of the program is not passed
+// to the command, since it may contain credentials.
+func (r *Runner) environment(event string) (env []string) {
+	server := r.tunnel.ServerName
+	if server == "" && r.tunnel.ServerIP != nil {
+		server = r.tunnel.ServerIP.String()
+	}
+
+	serverIP := ""
+	if r.tunnel.ServerIP != nil {
+		serverIP = r.tunnel.ServerIP.String()
+	}
+
+	publicIP := ""
+	if r.publicIP != nil {
+		publicIP = r.publicIP.String()
+	}
+
+	forwardedPort := ""
+	if r.port != 0 {
+		forwardedPort = fmt.Sprint(r.port)
+	}
+
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"GLUETUN_EVENT=" + event,
+		"GLUETUN_VPN_TYPE=" + r.tunnel.VPNType,
+		"GLUETUN_VPN_INTERFACE=" + r.tunnel.Interface,
+		"GLUETUN_VPN_SERVER=" + server,
+		"GLUETUN_VPN_SERVER_IP=" + serverIP,
+		"GLUETUN_PUBLIC_IP=" + publicIP,
+		"GLUETUN_FORWARDED_PORT=" + forwardedPort,
+	}
+}
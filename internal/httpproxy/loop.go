@@ -7,6 +7,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/httpproxy/state"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
@@ -36,14 +37,15 @@ type Loop struct {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(logger Logger, settings settings.HTTPProxy) *Loop {
+func NewLoop(logger Logger, settings settings.HTTPProxy,
+	publisher events.Publisher) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 
 	statusManager := loopstate.New(constants.Stopped,
-		start, running, stop, stopped)
+		start, running, stop, stopped, "http proxy", publisher)
 	state := state.New(statusManager, settings)
 
 	return &Loop{
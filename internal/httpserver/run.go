@@ -2,9 +2,12 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 )
 
 // Run runs the HTTP server until ctx is canceled.
@@ -32,7 +35,7 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		}
 	}()
 
-	listener, err := net.Listen("tcp", s.address)
+	listener, err := s.listen()
 	if err != nil {
 		close(s.addressSet)
 		close(crashed) // stop shutdown goroutine
@@ -42,11 +45,17 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		return
 	}
 
-	s.address = listener.Addr().String()
+	if _, isUnix := UnixSocketPath(s.address); !isUnix {
+		s.address = listener.Addr().String()
+	}
 	close(s.addressSet)
 
 	// note: no further write so no need to mutex
-	s.logger.Info("http server listening on " + s.address)
+	scheme := "http"
+	if s.tlsConfig != nil {
+		scheme = "https"
+	}
+	s.logger.Info(scheme + " server listening on " + s.address)
 	close(ready)
 
 	err = server.Serve(listener)
@@ -63,3 +72,43 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 	}
 	close(done)
 }
+
+func (s *Server) listen() (listener net.Listener, err error) {
+	return listen(s.address, s.tlsConfig)
+}
+
+// Listen listens on the TCP address or on the Unix domain socket
+// address, and wraps the listener with TLS if TLS is enabled.
+// It is used by HTTP servers not using the Server implementation.
+func Listen(address string, tlsSettings TLS) (listener net.Listener, err error) {
+	tlsConfig, err := tlsSettings.makeConfig()
+	if err != nil {
+		return nil, err
+	}
+	return listen(address, tlsConfig)
+}
+
+// listen listens on the TCP address or on the Unix domain socket
+// path, and wraps the listener with TLS if the TLS config is not nil.
+func listen(address string, tlsConfig *tls.Config) (listener net.Listener, err error) {
+	network := "tcp"
+	if path, isUnix := UnixSocketPath(address); isUnix {
+		network, address = "unix", path
+		// Remove any socket file left over from a previous run
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("cannot remove existing unix socket: %w", err)
+		}
+	}
+
+	listener, err = net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	return listener, nil
+}
@@ -2,12 +2,16 @@ package httpserver
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
 
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Server_Run_success(t *testing.T) {
@@ -70,3 +74,38 @@ func Test_Server_Run_failure(t *testing.T) {
 		assert.False(t, ok)
 	}
 }
+
+func Test_Server_Run_unixSocket(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	socketPath := filepath.Join(t.TempDir(), "server.sock")
+	address := UnixSocketPrefix + socketPath
+
+	logger := NewMockLogger(ctrl)
+	logger.EXPECT().Info("http server listening on " + address)
+
+	server := &Server{
+		address:         address,
+		addressSet:      make(chan struct{}),
+		handler:         http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		logger:          logger,
+		shutdownTimeout: time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan struct{})
+
+	go server.Run(ctx, ready, done)
+	<-ready
+
+	assert.Equal(t, address, server.GetAddress())
+	connection, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	err = connection.Close()
+	require.NoError(t, err)
+
+	cancel()
+	<-done
+}
@@ -3,6 +3,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"time"
@@ -34,6 +35,7 @@ type Server struct {
 	handler         http.Handler
 	logger          Logger
 	shutdownTimeout time.Duration
+	tlsConfig       *tls.Config
 }
 
 // New creates a new HTTP server with the given settings.
@@ -45,11 +47,17 @@ func New(settings Settings) (s *Server, err error) {
 		return nil, fmt.Errorf("http server settings validation failed: %w", err)
 	}
 
+	tlsConfig, err := settings.TLS.makeConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Server{
 		address:         settings.Address,
 		addressSet:      make(chan struct{}),
 		handler:         settings.Handler,
 		logger:          settings.Logger,
 		shutdownTimeout: *settings.ShutdownTimeout,
+		tlsConfig:       tlsConfig,
 	}, nil
 }
@@ -14,6 +14,8 @@ import (
 
 type Settings struct {
 	// Address is the server listening address.
+	// It can be a Unix domain socket address prefixed
+	// with unix: such as unix:/tmp/gluetun.sock.
 	// It defaults to :8000.
 	Address string
 	// Handler is the HTTP Handler to use.
@@ -25,6 +27,9 @@ type Settings struct {
 	// ShutdownTimeout is the shutdown timeout duration
 	// of the HTTP server. It defaults to 3 seconds.
 	ShutdownTimeout *time.Duration
+	// TLS contains settings to serve HTTPS.
+	// TLS is disabled by default.
+	TLS TLS
 }
 
 func (s *Settings) SetDefaults() {
@@ -39,6 +44,7 @@ func (s Settings) Copy() Settings {
 		Handler:         s.Handler,
 		Logger:          s.Logger,
 		ShutdownTimeout: helpers.CopyDurationPtr(s.ShutdownTimeout),
+		TLS:             s.TLS.Copy(),
 	}
 }
 
@@ -49,6 +55,7 @@ func (s *Settings) MergeWith(other Settings) {
 		s.Logger = other.Logger
 	}
 	s.ShutdownTimeout = helpers.MergeWithDuration(s.ShutdownTimeout, other.ShutdownTimeout)
+	s.TLS.MergeWith(other.TLS)
 }
 
 func (s *Settings) OverrideWith(other Settings) {
@@ -58,6 +65,7 @@ func (s *Settings) OverrideWith(other Settings) {
 		s.Logger = other.Logger
 	}
 	s.ShutdownTimeout = helpers.OverrideWithDuration(s.ShutdownTimeout, other.ShutdownTimeout)
+	s.TLS.OverrideWith(other.TLS)
 }
 
 var (
@@ -67,8 +75,12 @@ var (
 )
 
 func (s Settings) Validate() (err error) {
-	uid := os.Getuid()
-	_, err = address.Validate(s.Address, address.OptionListening(uid))
+	if path, ok := UnixSocketPath(s.Address); ok {
+		err = validateUnixSocketPath(path)
+	} else {
+		uid := os.Getuid()
+		_, err = address.Validate(s.Address, address.OptionListening(uid))
+	}
 	if err != nil {
 		return err
 	}
@@ -88,6 +100,11 @@ func (s Settings) Validate() (err error) {
 			*s.ShutdownTimeout, minShutdownTimeout)
 	}
 
+	err = s.TLS.Validate()
+	if err != nil {
+		return fmt.Errorf("TLS: %w", err)
+	}
+
 	return nil
 }
 
@@ -95,6 +112,9 @@ func (s Settings) ToLinesNode() (node *gotree.Node) {
 	node = gotree.New("HTTP server settings:")
 	node.Appendf("Listening address: %s", s.Address)
 	node.Appendf("Shutdown timeout: %s", *s.ShutdownTimeout)
+	if s.TLS.Enabled() {
+		node.AppendNode(s.TLS.ToLinesNode())
+	}
 	return node
 }
 
@@ -0,0 +1,117 @@
+package httpserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gotree"
+)
+
+// TLS contains settings to serve HTTPS instead of HTTP.
+type TLS struct {
+	// CertFilepath is the path to the PEM encoded TLS certificate
+	// file. TLS is disabled if it is the empty string.
+	CertFilepath string
+	// KeyFilepath is the path to the PEM encoded TLS key file.
+	// It must be set if CertFilepath is set.
+	KeyFilepath string
+	// ClientCAFilepath is the path to the PEM encoded certificate
+	// authorities file used to verify client certificates.
+	// Client certificates are not verified if it is the empty string.
+	ClientCAFilepath string
+}
+
+// Enabled returns true if TLS is enabled.
+func (t TLS) Enabled() bool {
+	return t.CertFilepath != ""
+}
+
+func (t TLS) Copy() TLS {
+	return t
+}
+
+func (t *TLS) MergeWith(other TLS) {
+	t.CertFilepath = helpers.MergeWithString(t.CertFilepath, other.CertFilepath)
+	t.KeyFilepath = helpers.MergeWithString(t.KeyFilepath, other.KeyFilepath)
+	t.ClientCAFilepath = helpers.MergeWithString(t.ClientCAFilepath, other.ClientCAFilepath)
+}
+
+func (t *TLS) OverrideWith(other TLS) {
+	t.CertFilepath = helpers.OverrideWithString(t.CertFilepath, other.CertFilepath)
+	t.KeyFilepath = helpers.OverrideWithString(t.KeyFilepath, other.KeyFilepath)
+	t.ClientCAFilepath = helpers.OverrideWithString(t.ClientCAFilepath, other.ClientCAFilepath)
+}
+
+var (
+	ErrTLSCertFileMissing   = errors.New("TLS certificate file path is not set")
+	ErrTLSKeyFileMissing    = errors.New("TLS key file path is not set")
+	ErrTLSClientCANoCert    = errors.New("TLS client certificate authorities cannot be used without TLS")
+	ErrTLSClientCANotParsed = errors.New("no certificate found in TLS client certificate authorities file")
+)
+
+func (t TLS) Validate() (err error) {
+	switch {
+	case t.CertFilepath == "" && t.KeyFilepath != "":
+		return fmt.Errorf("%w", ErrTLSCertFileMissing)
+	case t.CertFilepath != "" && t.KeyFilepath == "":
+		return fmt.Errorf("%w", ErrTLSKeyFileMissing)
+	case t.CertFilepath == "" && t.ClientCAFilepath != "":
+		return fmt.Errorf("%w", ErrTLSClientCANoCert)
+	}
+	return nil
+}
+
+func (t TLS) ToLinesNode() (node *gotree.Node) {
+	node = gotree.New("TLS:")
+	node.Appendf("Certificate file: %s", t.CertFilepath)
+	node.Appendf("Key file: %s", t.KeyFilepath)
+	if t.ClientCAFilepath != "" {
+		node.Appendf("Client certificate authorities file: %s", t.ClientCAFilepath)
+	}
+	return node
+}
+
+func (t TLS) String() string {
+	return t.ToLinesNode().String()
+}
+
+// makeConfig loads the certificate, key and client certificate
+// authorities files to create a TLS configuration. It returns
+// a nil configuration if TLS is disabled.
+func (t TLS) makeConfig() (config *tls.Config, err error) {
+	if !t.Enabled() {
+		return nil, nil //nolint:nilnil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(t.CertFilepath, t.KeyFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load TLS key pair: %w", err)
+	}
+
+	config = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if t.ClientCAFilepath == "" {
+		return config, nil
+	}
+
+	caPEM, err := os.ReadFile(t.ClientCAFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read TLS client certificate authorities: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("%w: %s", ErrTLSClientCANotParsed, t.ClientCAFilepath)
+	}
+	config.ClientCAs = clientCAs
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return config, nil
+}
@@ -0,0 +1,208 @@
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TLS_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		tls        TLS
+		errWrapped error
+	}{
+		"disabled": {},
+		"missing key": {
+			tls:        TLS{CertFilepath: "cert.pem"},
+			errWrapped: ErrTLSKeyFileMissing,
+		},
+		"missing certificate": {
+			tls:        TLS{KeyFilepath: "key.pem"},
+			errWrapped: ErrTLSCertFileMissing,
+		},
+		"client CA without certificate": {
+			tls:        TLS{ClientCAFilepath: "ca.pem"},
+			errWrapped: ErrTLSClientCANoCert,
+		},
+		"valid": {
+			tls: TLS{
+				CertFilepath:     "cert.pem",
+				KeyFilepath:      "key.pem",
+				ClientCAFilepath: "ca.pem",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.tls.Validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+// writeCertificate generates an ECDSA key and a certificate from the
+// template, signed by the parent certificate and key, or self-signed
+// if the parent is nil. It writes both PEM encoded in the directory
+// and returns the certificate, key and their file paths.
+func writeCertificate(t *testing.T, dir, name string,
+	template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	certificate *x509.Certificate, key *ecdsa.PrivateKey,
+	certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template,
+		parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	certificate, err = x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath = filepath.Join(dir, name+".crt")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	err = os.WriteFile(certPath, certPEM, 0600)
+	require.NoError(t, err)
+
+	keyPath = filepath.Join(dir, name+".key")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	err = os.WriteFile(keyPath, keyPEM, 0600)
+	require.NoError(t, err)
+
+	return certificate, key, certPath, keyPath
+}
+
+func Test_TLS_makeConfig_clientCA(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(time.Hour)
+
+	caCert, caKey, caPath, _ := writeCertificate(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	_, _, serverCertPath, serverKeyPath := writeCertificate(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "server"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caCert, caKey)
+
+	_, _, clientCertPath, clientKeyPath := writeCertificate(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+
+	_, _, otherCertPath, otherKeyPath := writeCertificate(t, dir, "other", &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "other"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil, nil)
+
+	tlsSettings := TLS{
+		CertFilepath:     serverCertPath,
+		KeyFilepath:      serverKeyPath,
+		ClientCAFilepath: caPath,
+	}
+	config, err := tlsSettings.makeConfig()
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+
+	listener, err := listen("127.0.0.1:0", config)
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(caCert)
+	get := func(certPath, keyPath string) (err error) {
+		clientConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		if certPath != "" {
+			certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+			require.NoError(t, err)
+			clientConfig.Certificates = []tls.Certificate{certificate}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		response, err := client.Get("https://" + listener.Addr().String())
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	err = get(clientCertPath, clientKeyPath)
+	assert.NoError(t, err)
+
+	err = get("", "")
+	assert.Error(t, err)
+
+	err = get(otherCertPath, otherKeyPath)
+	assert.Error(t, err)
+}
+
+func Test_TLS_makeConfig_clientCANotParsed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, _, certPath, keyPath := writeCertificate(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, nil, nil)
+	caPath := filepath.Join(dir, "ca.pem")
+	err := os.WriteFile(caPath, []byte("not a certificate"), 0600)
+	require.NoError(t, err)
+
+	tlsSettings := TLS{
+		CertFilepath:     certPath,
+		KeyFilepath:      keyPath,
+		ClientCAFilepath: caPath,
+	}
+	_, err = tlsSettings.makeConfig()
+
+	assert.ErrorIs(t, err, ErrTLSClientCANotParsed)
+}
@@ -0,0 +1,30 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// UnixSocketPrefix is the address prefix to listen
+// on a Unix domain socket, for example unix:/tmp/gluetun.sock.
+const UnixSocketPrefix = "unix:"
+
+// UnixSocketPath returns the Unix domain socket file path
+// and true if the address is a Unix socket address.
+func UnixSocketPath(address string) (path string, ok bool) {
+	if !strings.HasPrefix(address, UnixSocketPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(address, UnixSocketPrefix), true
+}
+
+var ErrUnixSocketPathNotAbsolute = errors.New("unix socket path is not absolute")
+
+func validateUnixSocketPath(path string) (err error) {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s", ErrUnixSocketPathNotAbsolute, path)
+	}
+	return nil
+}
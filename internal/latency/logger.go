@@ -0,0 +1,6 @@
+package latency
+
+type Logger interface {
+	Debug(s string)
+	Warn(s string)
+}
@@ -0,0 +1,189 @@
+// Package latency measures the latency to VPN servers in order
+// to pick the closest one.
+package latency
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	defaultTimeout      = 2 * time.Second
+	defaultCacheTTL     = 10 * time.Minute
+	defaultUDPProbePort = 443
+)
+
+type Prober struct {
+	firewall firewall.ProbeAllower
+	logger   Logger
+	timeout  time.Duration
+	cacheTTL time.Duration
+	// udpProbePort is the TCP port probed on the host
+	// of connections using UDP.
+	udpProbePort uint16
+	timeNow      func() time.Time
+	cache        map[string]cacheEntry
+	cacheMu      sync.Mutex
+}
+
+type cacheEntry struct {
+	latency  time.Duration
+	measured time.Time
+}
+
+// New creates a latency prober. The firewall given is used
+// to allow traffic to the connections being probed.
+func New(firewall firewall.ProbeAllower, logger Logger) *Prober {
+	return &Prober{
+		firewall:     firewall,
+		logger:       logger,
+		timeout:      defaultTimeout,
+		cacheTTL:     defaultCacheTTL,
+		udpProbePort: defaultUDPProbePort,
+		timeNow:      time.Now,
+		cache:        make(map[string]cacheEntry),
+	}
+}
+
+// ProbeLatencies returns the latency to each of the connections
+// given, measured with a TCP connection to the connection port for
+// TCP connections, and to the udpProbePort of the connection host
+// for UDP connections, since UDP VPN servers do not reply to data
+// they cannot authenticate. The latency is 0 for connections
+// which cannot be reached. Latencies measured recently are
+// returned from a cache instead of being measured again.
+func (p *Prober) ProbeLatencies(connections []models.Connection) (
+	latencies []time.Duration) {
+	latencies = make([]time.Duration, len(connections))
+	toProbe := make([]models.Connection, 0, len(connections))
+	probeConnections := make([]models.Connection, 0, len(connections))
+	toProbeIndexes := make([]int, 0, len(connections))
+	for i, connection := range connections {
+		latency, ok := p.cached(connection)
+		if ok {
+			latencies[i] = latency
+			continue
+		}
+		toProbe = append(toProbe, connection)
+		probeConnections = append(probeConnections, p.probeConnection(connection))
+		toProbeIndexes = append(toProbeIndexes, i)
+	}
+
+	if len(toProbe) == 0 {
+		return latencies
+	}
+
+	ctx := context.Background()
+	err := p.firewall.SetProbeConnections(ctx, probeConnections)
+	if err != nil {
+		p.logger.Warn("cannot allow latency probes through firewall: " + err.Error())
+		return latencies
+	}
+	defer func() {
+		err := p.firewall.SetProbeConnections(ctx, nil)
+		if err != nil {
+			p.logger.Warn("cannot remove latency probes from firewall: " + err.Error())
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i, connection := range toProbe {
+		wg.Add(1)
+		go func(index int, connection, probeConnection models.Connection) {
+			defer wg.Done()
+			latency := p.probe(ctx, probeConnection)
+			latencies[index] = latency
+			p.setCached(connection, latency)
+		}(toProbeIndexes[i], connection, probeConnections[i])
+	}
+	wg.Wait()
+
+	return latencies
+}
+
+// Latency returns the latency last measured for the connection
+// given, and false if it was not measured recently.
+func (p *Prober) Latency(connection models.Connection) (
+	latency time.Duration, ok bool) {
+	return p.cached(connection)
+}
+
+// probeConnection returns the TCP connection to probe to
+// measure the latency to the connection given.
+func (p *Prober) probeConnection(connection models.Connection) (
+	probeConnection models.Connection) {
+	if connection.Protocol == constants.TCP {
+		return connection
+	}
+	return models.Connection{
+		Type:     connection.Type,
+		IP:       connection.IP,
+		Port:     p.udpProbePort,
+		Protocol: constants.TCP,
+	}
+}
+
+// probe returns the time taken to establish a TCP connection
+// to the connection given, or 0 if it cannot be reached.
+func (p *Prober) probe(ctx context.Context, connection models.Connection) (
+	latency time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(connection.IP.String(), strconv.Itoa(int(connection.Port)))
+
+	start := p.timeNow()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err == nil {
+		err = conn.Close()
+	}
+
+	// A refused connection means the server host answered,
+	// so the time taken is still a valid round trip time.
+	if err != nil && !errors.Is(err, syscall.ECONNREFUSED) {
+		p.logger.Debug("cannot reach " + address + ": " + err.Error())
+		return 0
+	}
+
+	latency = p.timeNow().Sub(start)
+	if latency == 0 { // 0 is reserved for unreachable connections
+		latency = time.Nanosecond
+	}
+	p.logger.Debug("latency to " + address + " is " + latency.String())
+	return latency
+}
+
+func (p *Prober) cached(connection models.Connection) (
+	latency time.Duration, ok bool) {
+	p.cacheMu.Lock()
+	defer p.cacheMu.Unlock()
+	entry, ok := p.cache[cacheKey(connection)]
+	if !ok || p.timeNow().Sub(entry.measured) > p.cacheTTL {
+		return 0, false
+	}
+	return entry.latency, true
+}
+
+func (p *Prober) setCached(connection models.Connection, latency time.Duration) {
+	p.cacheMu.Lock()
+	defer p.cacheMu.Unlock()
+	p.cache[cacheKey(connection)] = cacheEntry{
+		latency:  latency,
+		measured: p.timeNow(),
+	}
+}
+
+func cacheKey(connection models.Connection) string {
+	return connection.Protocol + "://" + net.JoinHostPort(
+		connection.IP.String(), strconv.Itoa(int(connection.Port)))
+}
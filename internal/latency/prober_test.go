@@ -0,0 +1,116 @@
+package latency
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFirewall struct {
+	connections [][]models.Connection
+}
+
+func (f *testFirewall) SetProbeConnections(_ context.Context,
+	connections []models.Connection) error {
+	f.connections = append(f.connections, connections)
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Warn(string)  {}
+
+func Test_Prober_ProbeLatencies(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	listeningPort := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	// Find a port with no TCP listener, which
+	// refuses connections but is still reachable.
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := uint16(closedListener.Addr().(*net.TCPAddr).Port)
+	err = closedListener.Close()
+	require.NoError(t, err)
+
+	firewall := &testFirewall{}
+	prober := New(firewall, noopLogger{})
+	prober.timeout = 100 * time.Millisecond
+
+	connections := []models.Connection{
+		{IP: net.IPv4(127, 0, 0, 1), Port: listeningPort, Protocol: constants.TCP},
+		{IP: net.IPv4(127, 0, 0, 1), Port: closedPort, Protocol: constants.TCP},
+	}
+
+	latencies := prober.ProbeLatencies(connections)
+
+	require.Len(t, latencies, 2)
+	assert.NotZero(t, latencies[0])
+	assert.NotZero(t, latencies[1])
+	expectedFirewallCalls := [][]models.Connection{connections, nil}
+	assert.Equal(t, expectedFirewallCalls, firewall.connections)
+
+	latency, ok := prober.Latency(connections[0])
+	assert.True(t, ok)
+	assert.Equal(t, latencies[0], latency)
+
+	// Latencies are cached so the firewall is not changed.
+	cachedLatencies := prober.ProbeLatencies(connections)
+	assert.Equal(t, latencies, cachedLatencies)
+	assert.Len(t, firewall.connections, 2)
+
+	// Cached latencies expire.
+	prober.timeNow = func() time.Time { return time.Now().Add(prober.cacheTTL + time.Second) }
+	_, ok = prober.Latency(connections[0])
+	assert.False(t, ok)
+}
+
+func Test_Prober_ProbeLatencies_udp(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	listeningPort := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	firewall := &testFirewall{}
+	prober := New(firewall, noopLogger{})
+	prober.timeout = 100 * time.Millisecond
+	prober.udpProbePort = listeningPort
+
+	// No UDP server listens on port 1194, yet the
+	// latency is measured through the TCP probe port.
+	connections := []models.Connection{
+		{Type: constants.OpenVPN, IP: net.IPv4(127, 0, 0, 1),
+			Port: 1194, Protocol: constants.UDP},
+	}
+
+	latencies := prober.ProbeLatencies(connections)
+
+	require.Len(t, latencies, 1)
+	assert.NotZero(t, latencies[0])
+	expectedProbeConnections := []models.Connection{
+		{Type: constants.OpenVPN, IP: net.IPv4(127, 0, 0, 1),
+			Port: listeningPort, Protocol: constants.TCP},
+	}
+	expectedFirewallCalls := [][]models.Connection{expectedProbeConnections, nil}
+	assert.Equal(t, expectedFirewallCalls, firewall.connections)
+
+	latency, ok := prober.Latency(connections[0])
+	assert.True(t, ok)
+	assert.Equal(t, latencies[0], latency)
+}
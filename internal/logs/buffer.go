@@ -0,0 +1,156 @@
+// Package logs defines a bounded in-memory buffer of
+// recent log lines, which can be queried and followed.
+package logs
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/qdm12/log"
+)
+
+// Buffer is a ring buffer of the most recent log lines.
+// It implements io.Writer so it can be set as a logger writer.
+type Buffer struct {
+	mutex       sync.RWMutex
+	lines       []Line
+	start       int
+	count       int
+	partial     []byte
+	subscribers map[chan Line]struct{}
+	timeNow     func() time.Time
+}
+
+// New creates a buffer keeping at most the given number of lines.
+func New(capacity int) *Buffer {
+	return &Buffer{
+		lines:       make([]Line, capacity),
+		subscribers: make(map[chan Line]struct{}),
+		timeNow:     time.Now,
+	}
+}
+
+// Filter contains criteria to select log lines.
+// Each zero valued field does not filter lines.
+type Filter struct {
+	Component string
+	// MinLevel is the least severe level to keep,
+	// for example log.LevelWarn keeps warnings and errors.
+	MinLevel log.Level
+	Since    time.Time
+}
+
+func (f Filter) matches(line Line) bool {
+	return (f.Component == "" || line.Component == f.Component) &&
+		line.level <= f.MinLevel &&
+		(f.Since.IsZero() || !line.Time.Before(f.Since))
+}
+
+// Write parses each complete line written and stores it in the
+// buffer, overwriting the oldest line if the buffer is full.
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	data := append(b.partial, p...) //nolint:gocritic
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := parseLine(string(data[:i]), b.timeNow())
+		data = data[i+1:]
+		b.add(line)
+	}
+	b.partial = append([]byte(nil), data...)
+
+	return len(p), nil
+}
+
+// add must be called with the mutex locked.
+func (b *Buffer) add(line Line) {
+	capacity := len(b.lines)
+	if capacity == 0 {
+		return
+	}
+
+	if b.count < capacity {
+		b.lines[(b.start+b.count)%capacity] = line
+		b.count++
+	} else {
+		b.lines[b.start] = line
+		b.start = (b.start + 1) % capacity
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- line:
+		default: // drop line for slow subscriber
+		}
+	}
+}
+
+// Lines returns the buffered lines matching the filter,
+// from the oldest to the most recent.
+func (b *Buffer) Lines(filter Filter) (lines []Line) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.filteredLines(filter)
+}
+
+// filteredLines must be called with the mutex locked.
+func (b *Buffer) filteredLines(filter Filter) (lines []Line) {
+	lines = make([]Line, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		line := b.lines[(b.start+i)%len(b.lines)]
+		if filter.matches(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Follow returns the buffered lines matching the filter, and a channel
+// receiving lines written afterwards and matching the filter.
+// The unsubscribe function must be called once the caller is no
+// longer reading from the channel.
+func (b *Buffer) Follow(filter Filter) (lines []Line,
+	newLines <-chan Line, unsubscribe func()) {
+	const bufferSize = 64
+	subscriber := make(chan Line, bufferSize)
+	filtered := make(chan Line)
+	done := make(chan struct{})
+
+	b.mutex.Lock()
+	lines = b.filteredLines(filter)
+	b.subscribers[subscriber] = struct{}{}
+	b.mutex.Unlock()
+
+	go func() {
+		defer close(filtered)
+		for line := range subscriber {
+			if !filter.matches(line) {
+				continue
+			}
+			select {
+			case filtered <- line:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			close(done)
+			b.mutex.Lock()
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+			b.mutex.Unlock()
+		})
+	}
+
+	return lines, filtered, unsubscribe
+}
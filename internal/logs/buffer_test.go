@@ -0,0 +1,89 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Buffer(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 3
+	buffer := New(capacity)
+
+	_, err := buffer.Write([]byte("2022-01-01T00:00:00Z INFO [vpn] first\n"))
+	require.NoError(t, err)
+	_, err = buffer.Write([]byte("2022-01-01T00:00:01Z \x1b[33mWARN\x1b[0m [vpn] second\n" +
+		"2022-01-01T00:00:02Z ERROR [dns] third\n2022-01-01T00:00:03Z DEBUG [vpn] "))
+	require.NoError(t, err)
+	_, err = buffer.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+
+	lines := buffer.Lines(Filter{MinLevel: log.LevelDebug})
+	require.Len(t, lines, capacity)
+	assert.Equal(t, Line{
+		Time:      time.Date(2022, 1, 1, 0, 0, 1, 0, time.UTC),
+		Level:     "warn",
+		Component: "vpn",
+		Message:   "second",
+		level:     log.LevelWarn,
+	}, lines[0])
+	assert.Equal(t, "third", lines[1].Message)
+	assert.Equal(t, "fourth", lines[2].Message)
+
+	lines = buffer.Lines(Filter{Component: "vpn", MinLevel: log.LevelWarn})
+	require.Len(t, lines, 1)
+	assert.Equal(t, "second", lines[0].Message)
+
+	lines = buffer.Lines(Filter{MinLevel: log.LevelDebug,
+		Since: time.Date(2022, 1, 1, 0, 0, 2, 0, time.UTC)})
+	require.Len(t, lines, 2)
+	assert.Equal(t, "third", lines[0].Message)
+
+	lines, newLines, unsubscribe := buffer.Follow(Filter{Component: "dns", MinLevel: log.LevelInfo})
+	defer unsubscribe()
+	require.Len(t, lines, 1)
+	_, err = buffer.Write([]byte("2022-01-01T00:00:04Z INFO [vpn] ignored\n" +
+		"2022-01-01T00:00:05Z INFO [dns] followed\n"))
+	require.NoError(t, err)
+	line := <-newLines
+	assert.Equal(t, "followed", line.Message)
+}
+
+func Test_parseLine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1, 0)
+
+	testCases := map[string]struct {
+		s    string
+		line Line
+	}{
+		"no component": {
+			s: "2022-01-01T00:00:00Z INFO message [a] b",
+			line: Line{
+				Time:    time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				Level:   "info",
+				Message: "message [a] b",
+				level:   log.LevelInfo,
+			},
+		},
+		"unparsable": {
+			s:    "some output",
+			line: Line{Time: now, Level: "info", Message: "some output", level: log.LevelInfo},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			line := parseLine(testCase.s, now)
+			assert.Equal(t, testCase.line, line)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/qdm12/log"
+)
+
+// Line is a log line kept in the buffer.
+type Line struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Component string    `json:"component,omitempty"`
+	Message   string    `json:"message"`
+	level     log.Level
+}
+
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// parseLine parses a line written by a qdm12/log logger,
+// in the format `<time> <LEVEL> [component] message`.
+// The component is optional, and the line is kept as a
+// message if it cannot be parsed.
+func parseLine(s string, now time.Time) (line Line) {
+	s = ansiEscapeRegex.ReplaceAllString(s, "")
+	line.Time = now
+	line.setLevel(log.LevelInfo)
+
+	fields := strings.SplitN(s, " ", 3) //nolint:gomnd
+	const minFields = 3
+	if len(fields) < minFields {
+		line.Message = s
+		return line
+	}
+
+	t, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		line.Message = s
+		return line
+	}
+
+	level, err := log.ParseLevel(fields[1])
+	if err != nil {
+		line.Message = s
+		return line
+	}
+
+	line.Time = t
+	line.setLevel(level)
+	line.Message = fields[2]
+
+	if strings.HasPrefix(line.Message, "[") {
+		end := strings.Index(line.Message, "] ")
+		if end > 0 {
+			line.Component = line.Message[1:end]
+			line.Message = line.Message[end+2:]
+		}
+	}
+
+	return line
+}
+
+func (l *Line) setLevel(level log.Level) {
+	l.level = level
+	l.Level = strings.ToLower(level.String())
+}
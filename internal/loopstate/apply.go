@@ -41,7 +41,7 @@ func (s *State) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 			return "already " + existingStatus.String(), nil
 		}
 
-		s.status = constants.Starting
+		s.setStatus(constants.Starting)
 		s.statusMu.Unlock()
 		s.start <- struct{}{}
 
@@ -61,7 +61,7 @@ func (s *State) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 			return "already " + existingStatus.String(), nil
 		}
 
-		s.status = constants.Stopping
+		s.setStatus(constants.Stopping)
 		s.statusMu.Unlock()
 		s.stop <- struct{}{}
 
@@ -1,6 +1,9 @@
 package loopstate
 
-import "github.com/qdm12/gluetun/internal/models"
+import (
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+)
 
 type Setter interface {
 	SetStatus(status models.LoopStatus)
@@ -12,5 +15,15 @@ type Setter interface {
 func (s *State) SetStatus(status models.LoopStatus) {
 	s.statusMu.Lock()
 	defer s.statusMu.Unlock()
+	s.setStatus(status)
+}
+
+// setStatus sets the status and publishes a status event
+// if it changed. It must be called with the status mutex locked.
+func (s *State) setStatus(status models.LoopStatus) {
+	if s.status == status {
+		return
+	}
 	s.status = status
+	s.publisher.Publish(s.component, events.TypeStatus, status)
 }
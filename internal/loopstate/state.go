@@ -3,6 +3,7 @@ package loopstate
 import (
 	"sync"
 
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -15,15 +16,20 @@ type Manager interface {
 	Applier
 }
 
+// New creates a new loop state. The component name and publisher
+// are used to publish an event each time the loop status changes.
 func New(status models.LoopStatus,
 	start chan<- struct{}, running <-chan models.LoopStatus,
-	stop chan<- struct{}, stopped <-chan struct{}) *State {
+	stop chan<- struct{}, stopped <-chan struct{},
+	component string, publisher events.Publisher) *State {
 	return &State{
-		status:  status,
-		start:   start,
-		running: running,
-		stop:    stop,
-		stopped: stopped,
+		status:    status,
+		start:     start,
+		running:   running,
+		stop:      stop,
+		stopped:   stopped,
+		component: component,
+		publisher: publisher,
 	}
 }
 
@@ -37,4 +43,7 @@ type State struct {
 	running <-chan models.LoopStatus
 	stop    chan<- struct{}
 	stopped <-chan struct{}
+
+	component string
+	publisher events.Publisher
 }
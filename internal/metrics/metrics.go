@@ -0,0 +1,169 @@
+// Package metrics defines a Prometheus metrics collector
+// and an HTTP server to expose them.
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/netlink"
+)
+
+// Metrics collects metrics from events and from direct calls,
+// and writes them in the Prometheus text format.
+type Metrics struct {
+	vpn    VPNInterfaceGetter
+	linker LinkGetter
+
+	mutex               sync.RWMutex
+	loopStatuses        map[string]models.LoopStatus
+	vpnConnections      uint64
+	dnsStarts           uint64
+	updaterRuns         uint64
+	publicIPChanges     uint64
+	portForwarded       uint16
+	healthchecks        uint64
+	healthcheckFailures uint64
+	healthcheckDuration time.Duration
+	probes              map[probeKey]probeMetric
+}
+
+type probeKey struct {
+	probeType string
+	address   string
+}
+
+type probeMetric struct {
+	up      bool
+	latency time.Duration
+}
+
+// VPNInterfaceGetter gets the current VPN connection
+// and its network interface name.
+type VPNInterfaceGetter interface {
+	GetConnection() (connection models.Connection, vpnInterface string)
+}
+
+// LinkGetter gets a network link by name, to read
+// the VPN interface traffic statistics.
+type LinkGetter interface {
+	LinkByName(name string) (link netlink.Link, err error)
+}
+
+// New creates a new metrics collector. The vpn and linker
+// arguments are used to read the VPN interface statistics
+// when the metrics are written.
+func New(vpn VPNInterfaceGetter, linker LinkGetter) *Metrics {
+	return &Metrics{
+		vpn:          vpn,
+		linker:       linker,
+		loopStatuses: make(map[string]models.LoopStatus),
+		probes:       make(map[probeKey]probeMetric),
+	}
+}
+
+// Run collects metrics from the events received from the subscriber
+// until the context is canceled. The ready channel is closed once
+// subscribed to events.
+func (m *Metrics) Run(ctx context.Context, subscriber events.Subscriber,
+	ready, done chan<- struct{}) {
+	defer close(done)
+
+	eventsCh, unsubscribe := subscriber.Subscribe()
+	defer unsubscribe()
+	close(ready)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-eventsCh:
+			m.processEvent(event)
+		}
+	}
+}
+
+func (m *Metrics) processEvent(event events.Event) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	switch event.Type {
+	case events.TypeStatus:
+		status, ok := event.Data.(models.LoopStatus)
+		if !ok {
+			return
+		}
+		m.loopStatuses[event.Component] = status
+		if status != constants.Running {
+			return
+		}
+		switch event.Component {
+		case "vpn":
+			m.vpnConnections++
+		case "dns":
+			m.dnsStarts++
+		}
+	case events.TypePublicIP:
+		m.publicIPChanges++
+	case events.TypePortForwarded:
+		port, ok := event.Data.(uint16)
+		if ok {
+			m.portForwarded = port
+		}
+	case events.TypeUpdaterCompleted:
+		m.updaterRuns++
+	}
+}
+
+// HealthcheckDone records the result and duration of a health check.
+func (m *Metrics) HealthcheckDone(duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.healthchecks++
+	m.healthcheckDuration = duration
+	if err != nil {
+		m.healthcheckFailures++
+	}
+}
+
+// ProbeDone records the result and latency of a health check
+// target probe.
+func (m *Metrics) ProbeDone(probeType, address string,
+	latency time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := probeKey{probeType: probeType, address: address}
+	m.probes[key] = probeMetric{up: err == nil, latency: latency}
+}
+
+// sortedProbes returns the probes keys sorted by type and address.
+// It must be called with the mutex locked.
+func (m *Metrics) sortedProbes() (keys []probeKey) {
+	keys = make([]probeKey, 0, len(m.probes))
+	for key := range m.probes {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].probeType != keys[j].probeType {
+			return keys[i].probeType < keys[j].probeType
+		}
+		return keys[i].address < keys[j].address
+	})
+	return keys
+}
+
+// sortedComponents returns the components with a known loop status,
+// sorted alphabetically. It must be called with the mutex locked.
+func (m *Metrics) sortedComponents() (components []string) {
+	components = make([]string, 0, len(m.loopStatuses))
+	for component := range m.loopStatuses {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	return components
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vishvananda "github.com/vishvananda/netlink"
+)
+
+type testVPN struct {
+	vpnInterface string
+}
+
+func (t *testVPN) GetConnection() (connection models.Connection, vpnInterface string) {
+	return connection, t.vpnInterface
+}
+
+type testLinker struct {
+	link netlink.Link
+}
+
+func (t *testLinker) LinkByName(string) (link netlink.Link, err error) {
+	return t.link, nil
+}
+
+func Test_Metrics_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	link := &vishvananda.Tuntap{
+		LinkAttrs: vishvananda.LinkAttrs{
+			Statistics: &vishvananda.LinkStatistics{
+				RxBytes: 100,
+				TxBytes: 200,
+			},
+		},
+	}
+	metrics := New(&testVPN{vpnInterface: "tun0"}, &testLinker{link: link})
+
+	statusEvents := []models.LoopStatus{constants.Starting, constants.Running,
+		constants.Stopping, constants.Stopped, constants.Starting, constants.Running}
+	for _, status := range statusEvents {
+		metrics.processEvent(events.Event{Component: "vpn", Type: events.TypeStatus, Data: status})
+	}
+	metrics.processEvent(events.Event{Component: "dns", Type: events.TypeStatus, Data: constants.Running})
+	metrics.processEvent(events.Event{Type: events.TypePortForwarded, Data: uint16(5678)})
+	metrics.processEvent(events.Event{Type: events.TypePublicIP})
+	metrics.processEvent(events.Event{Type: events.TypeUpdaterCompleted})
+	metrics.HealthcheckDone(time.Second, nil)
+	metrics.HealthcheckDone(2*time.Second, errors.New("test"))
+	metrics.ProbeDone("tcp", "cloudflare.com:443", 500*time.Millisecond, nil)
+	metrics.ProbeDone("dns", "github.com", time.Second, errors.New("test"))
+
+	sb := new(strings.Builder)
+	_, err := metrics.WriteTo(sb)
+	require.NoError(t, err)
+	output := sb.String()
+
+	expectedLines := []string{
+		`gluetun_loop_status{component="dns",status="running"} 1`,
+		`gluetun_loop_status{component="vpn",status="running"} 1`,
+		`gluetun_loop_status{component="vpn",status="stopped"} 0`,
+		`gluetun_vpn_reconnects_total 1`,
+		`gluetun_healthchecks_total 2`,
+		`gluetun_healthcheck_failures_total 1`,
+		`gluetun_healthcheck_duration_seconds 2`,
+		`gluetun_healthcheck_probe_up{type="dns",address="github.com"} 0`,
+		`gluetun_healthcheck_probe_up{type="tcp",address="cloudflare.com:443"} 1`,
+		`gluetun_healthcheck_probe_latency_seconds{type="tcp",address="cloudflare.com:443"} 0.5`,
+		`gluetun_port_forwarded 5678`,
+		`gluetun_public_ip_changes_total 1`,
+		`gluetun_dns_restarts_total 0`,
+		`gluetun_updater_runs_total 1`,
+		`gluetun_vpn_interface_receive_bytes_total{interface="tun0"} 100`,
+		`gluetun_vpn_interface_transmit_bytes_total{interface="tun0"} 200`,
+	}
+	for _, expectedLine := range expectedLines {
+		assert.Contains(t, output, expectedLine+"\n")
+	}
+}
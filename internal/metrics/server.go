@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+)
+
+// NewServer creates a new HTTP server serving the metrics
+// at /metrics, configured with the settings given.
+// It returns an error if one of the settings is not valid.
+func NewServer(settings Settings, metrics *Metrics) (
+	server *httpserver.Server, err error) {
+	handler := http.NewServeMux()
+	handler.Handle("/metrics", metrics)
+	settings.HTTPServer.Handler = handler
+
+	settings.SetDefaults()
+	if err = settings.Validate(); err != nil {
+		return nil, fmt.Errorf("metrics settings failed validation: %w", err)
+	}
+
+	return httpserver.New(settings.HTTPServer)
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
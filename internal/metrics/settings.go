@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
+	"github.com/qdm12/gluetun/internal/httpserver"
+	"github.com/qdm12/gotree"
+)
+
+// Settings are the settings for the Prometheus metrics server.
+type Settings struct {
+	// Enabled can be false or true.
+	// It defaults to false.
+	Enabled *bool
+	// HTTPServer contains settings to configure
+	// the HTTP server serving the metrics.
+	HTTPServer httpserver.Settings
+}
+
+func (s *Settings) SetDefaults() {
+	s.Enabled = helpers.DefaultBool(s.Enabled, false)
+	s.HTTPServer.Address = helpers.DefaultString(s.HTTPServer.Address, ":9101")
+	s.HTTPServer.SetDefaults()
+}
+
+func (s Settings) Copy() (copied Settings) {
+	return Settings{
+		Enabled:    helpers.CopyBoolPtr(s.Enabled),
+		HTTPServer: s.HTTPServer.Copy(),
+	}
+}
+
+func (s *Settings) MergeWith(other Settings) {
+	s.Enabled = helpers.MergeWithBool(s.Enabled, other.Enabled)
+	s.HTTPServer.MergeWith(other.HTTPServer)
+}
+
+func (s *Settings) OverrideWith(other Settings) {
+	s.Enabled = helpers.OverrideWithBool(s.Enabled, other.Enabled)
+	s.HTTPServer.OverrideWith(other.HTTPServer)
+}
+
+func (s Settings) Validate() (err error) {
+	return s.HTTPServer.Validate()
+}
+
+func (s Settings) ToLinesNode() (node *gotree.Node) {
+	if !*s.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Metrics settings:")
+	node.AppendNode(s.HTTPServer.ToLinesNode())
+	return node
+}
+
+func (s Settings) String() string {
+	return s.ToLinesNode().String()
+}
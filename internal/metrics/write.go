@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var loopStatuses = []models.LoopStatus{ //nolint:gochecknoglobals
+	constants.Starting,
+	constants.Running,
+	constants.Stopping,
+	constants.Stopped,
+	constants.Crashed,
+	constants.Completed,
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (n int64, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sb := new(strings.Builder)
+
+	writeHeader(sb, "gluetun_loop_status", "gauge",
+		"Status of each loop, 1 for the current status and 0 otherwise.")
+	for _, component := range m.sortedComponents() {
+		currentStatus := m.loopStatuses[component]
+		for _, status := range loopStatuses {
+			value := 0
+			if status == currentStatus {
+				value = 1
+			}
+			fmt.Fprintf(sb, "gluetun_loop_status{component=%q,status=%q} %d\n",
+				component, status, value)
+		}
+	}
+
+	writeCounter(sb, "gluetun_vpn_reconnects_total",
+		"Number of times the VPN reconnected after its first connection.",
+		restarts(m.vpnConnections))
+	writeCounter(sb, "gluetun_healthchecks_total",
+		"Number of health checks run.", m.healthchecks)
+	writeCounter(sb, "gluetun_healthcheck_failures_total",
+		"Number of health checks which failed.", m.healthcheckFailures)
+	writeHeader(sb, "gluetun_healthcheck_duration_seconds", "gauge",
+		"Duration of the last health check in seconds.")
+	fmt.Fprintf(sb, "gluetun_healthcheck_duration_seconds %g\n",
+		m.healthcheckDuration.Seconds())
+	m.writeProbes(sb)
+	writeHeader(sb, "gluetun_port_forwarded", "gauge",
+		"VPN port forwarded, or 0 if no port is forwarded.")
+	fmt.Fprintf(sb, "gluetun_port_forwarded %d\n", m.portForwarded)
+	writeCounter(sb, "gluetun_public_ip_changes_total",
+		"Number of times the public IP information changed.", m.publicIPChanges)
+	writeCounter(sb, "gluetun_dns_restarts_total",
+		"Number of times the DNS server restarted after its first start.",
+		restarts(m.dnsStarts))
+	writeCounter(sb, "gluetun_updater_runs_total",
+		"Number of servers data updates completed.", m.updaterRuns)
+
+	m.writeInterfaceStatistics(sb)
+
+	written, err := io.WriteString(w, sb.String())
+	return int64(written), err
+}
+
+func (m *Metrics) writeProbes(sb *strings.Builder) {
+	if len(m.probes) == 0 {
+		return
+	}
+
+	keys := m.sortedProbes()
+	writeHeader(sb, "gluetun_healthcheck_probe_up", "gauge",
+		"Result of the last probe of each health check target, 1 if it passed and 0 otherwise.")
+	for _, key := range keys {
+		value := 0
+		if m.probes[key].up {
+			value = 1
+		}
+		fmt.Fprintf(sb, "gluetun_healthcheck_probe_up{type=%q,address=%q} %d\n",
+			key.probeType, key.address, value)
+	}
+	writeHeader(sb, "gluetun_healthcheck_probe_latency_seconds", "gauge",
+		"Latency of the last probe of each health check target in seconds.")
+	for _, key := range keys {
+		fmt.Fprintf(sb, "gluetun_healthcheck_probe_latency_seconds{type=%q,address=%q} %g\n",
+			key.probeType, key.address, m.probes[key].latency.Seconds())
+	}
+}
+
+func (m *Metrics) writeInterfaceStatistics(sb *strings.Builder) {
+	_, vpnInterface := m.vpn.GetConnection()
+	if vpnInterface == "" {
+		return
+	}
+
+	link, err := m.linker.LinkByName(vpnInterface)
+	if err != nil {
+		return
+	}
+
+	statistics := link.Attrs().Statistics
+	if statistics == nil {
+		return
+	}
+
+	writeHeader(sb, "gluetun_vpn_interface_receive_bytes_total", "counter",
+		"Number of bytes received on the VPN network interface.")
+	fmt.Fprintf(sb, "gluetun_vpn_interface_receive_bytes_total{interface=%q} %d\n",
+		vpnInterface, statistics.RxBytes)
+	writeHeader(sb, "gluetun_vpn_interface_transmit_bytes_total", "counter",
+		"Number of bytes transmitted on the VPN network interface.")
+	fmt.Fprintf(sb, "gluetun_vpn_interface_transmit_bytes_total{interface=%q} %d\n",
+		vpnInterface, statistics.TxBytes)
+}
+
+func writeHeader(sb *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeCounter(sb *strings.Builder, name, help string, value uint64) {
+	writeHeader(sb, name, "counter", help)
+	fmt.Fprintf(sb, "%s %d\n", name, value)
+}
+
+// restarts returns the number of restarts given the number of starts.
+func restarts(starts uint64) uint64 {
+	if starts == 0 {
+		return 0
+	}
+	return starts - 1
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"net"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
 )
 
 func (a AllServers) GetCopy() (servers AllServers) {
@@ -108,6 +110,66 @@ func (a *AllServers) GetWindscribe() (servers []Server) {
 	return copyServers(a.Windscribe.Servers)
 }
 
+// GetServersByProvider returns a copy of the servers for the
+// provider name given, and false if the provider has no servers data.
+func (a *AllServers) GetServersByProvider(provider string) (
+	servers []Server, ok bool) {
+	providerServers := a.ServersByProvider(provider)
+	if providerServers == nil {
+		return nil, false
+	}
+	return copyServers(providerServers.Servers), true
+}
+
+// ServersByProvider returns a pointer to the servers data of the
+// provider name given, or nil if the provider has no servers data.
+func (a *AllServers) ServersByProvider(provider string) *Servers { //nolint:cyclop
+	switch provider {
+	case providers.Cyberghost:
+		return &a.Cyberghost
+	case providers.Expressvpn:
+		return &a.Expressvpn
+	case providers.Fastestvpn:
+		return &a.Fastestvpn
+	case providers.HideMyAss:
+		return &a.HideMyAss
+	case providers.Ipvanish:
+		return &a.Ipvanish
+	case providers.Ivpn:
+		return &a.Ivpn
+	case providers.Mullvad:
+		return &a.Mullvad
+	case providers.Nordvpn:
+		return &a.Nordvpn
+	case providers.Perfectprivacy:
+		return &a.Perfectprivacy
+	case providers.Privado:
+		return &a.Privado
+	case providers.PrivateInternetAccess:
+		return &a.Pia
+	case providers.Privatevpn:
+		return &a.Privatevpn
+	case providers.Protonvpn:
+		return &a.Protonvpn
+	case providers.Purevpn:
+		return &a.Purevpn
+	case providers.Surfshark:
+		return &a.Surfshark
+	case providers.Torguard:
+		return &a.Torguard
+	case providers.VPNUnlimited:
+		return &a.VPNUnlimited
+	case providers.Vyprvpn:
+		return &a.Vyprvpn
+	case providers.Wevpn:
+		return &a.Wevpn
+	case providers.Windscribe:
+		return &a.Windscribe
+	default:
+		return nil
+	}
+}
+
 func copyServers(servers []Server) (serversCopy []Server) {
 	if servers == nil {
 		return nil
@@ -0,0 +1,27 @@
+package natpmp
+
+import (
+	"context"
+	"net"
+)
+
+// ExternalAddress requests the external IPv4 address of the gateway,
+// as defined in section 3.2 of RFC 6886.
+func (c *Client) ExternalAddress(ctx context.Context, gateway net.IP) (
+	externalIPv4Address net.IP, err error) {
+	const (
+		version       = 0
+		operationCode = 0
+		responseSize  = 12
+	)
+	request := []byte{version, operationCode}
+
+	response, err := c.rpc(ctx, gateway, request, responseSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bytes 4 to 8 are the seconds since start of epoch, which are ignored.
+	externalIPv4Address = net.IPv4(response[8], response[9], response[10], response[11])
+	return externalIPv4Address, nil
+}
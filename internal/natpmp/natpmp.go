@@ -0,0 +1,29 @@
+// Package natpmp implements a NAT-PMP client as defined in RFC 6886,
+// to obtain the external address of a gateway and to map ports on it.
+package natpmp
+
+import (
+	"time"
+)
+
+// Client is a NAT-PMP client.
+type Client struct {
+	serverPort   uint16
+	initialRetry time.Duration
+	maxRetries   int
+}
+
+// New creates a NAT-PMP client using the retransmission
+// values recommended in section 3.1 of RFC 6886.
+func New() *Client {
+	const (
+		serverPort   = 5351
+		initialRetry = 250 * time.Millisecond
+		maxRetries   = 9
+	)
+	return &Client{
+		serverPort:   serverPort,
+		initialRetry: initialRetry,
+		maxRetries:   maxRetries,
+	}
+}
@@ -0,0 +1,194 @@
+package natpmp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestResponder starts a local UDP NAT-PMP responder replying
+// to each request with the bytes returned by the respond function,
+// or not replying at all if it returns nil. It returns a client
+// configured to send its requests to the responder.
+func newTestResponder(t *testing.T,
+	respond func(request []byte) (response []byte)) (client *Client) {
+	t.Helper()
+
+	connection, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buffer := make([]byte, 1024)
+		for {
+			n, address, err := connection.ReadFromUDP(buffer)
+			if err != nil {
+				return // connection closed
+			}
+
+			response := respond(buffer[:n])
+			if response == nil {
+				continue
+			}
+			_, err = connection.WriteToUDP(response, address)
+			assert.NoError(t, err)
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = connection.Close()
+		wg.Wait()
+	})
+
+	return &Client{
+		serverPort:   uint16(connection.LocalAddr().(*net.UDPAddr).Port),
+		initialRetry: 10 * time.Millisecond,
+		maxRetries:   3,
+	}
+}
+
+func Test_Client_ExternalAddress(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		response   []byte
+		externalIP net.IP
+		err        error
+	}{
+		"success": {
+			response:   []byte{0, 128, 0, 0, 0, 0, 0, 1, 1, 2, 3, 4},
+			externalIP: net.IPv4(1, 2, 3, 4),
+		},
+		"no response": {
+			err: errors.New("connection timeout: after 3 tries"),
+		},
+		"result code not success": {
+			response: []byte{0, 128, 0, 3, 0, 0, 0, 1, 0, 0, 0, 0},
+			err:      errors.New("result code is not success: network failure"),
+		},
+		"operation code unknown": {
+			response: []byte{0, 129, 0, 0, 0, 0, 0, 1, 1, 2, 3, 4},
+			err:      errors.New("operation code is unknown: 129 instead of 128"),
+		},
+		"response too large": {
+			response: []byte{0, 128, 0, 0, 0, 0, 0, 1, 1, 2, 3, 4, 5},
+			err:      errors.New("response size is not valid: 13 bytes instead of 12 bytes"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestResponder(t, func(request []byte) []byte {
+				assert.Equal(t, []byte{0, 0}, request)
+				return testCase.response
+			})
+
+			externalIP, err := client.ExternalAddress(context.Background(),
+				net.IPv4(127, 0, 0, 1))
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.externalIP, externalIP)
+		})
+	}
+}
+
+func Test_Client_AddPortMapping(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		protocol         string
+		request          []byte
+		response         []byte
+		dropFirstRequest bool
+		assignedPort     uint16
+		assignedLifetime time.Duration
+		err              error
+	}{
+		"UDP": {
+			protocol:         "udp",
+			request:          []byte{0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 60},
+			response:         []byte{0, 129, 0, 0, 0, 0, 0, 1, 0, 1, 0xc3, 0x50, 0, 0, 0, 60},
+			assignedPort:     50000,
+			assignedLifetime: time.Minute,
+		},
+		"TCP after retransmission": {
+			protocol:         "tcp",
+			request:          []byte{0, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 60},
+			response:         []byte{0, 130, 0, 0, 0, 0, 0, 1, 0, 1, 0xc3, 0x50, 0, 0, 0, 60},
+			dropFirstRequest: true,
+			assignedPort:     50000,
+			assignedLifetime: time.Minute,
+		},
+		"protocol unknown": {
+			protocol: "sctp",
+			err:      errors.New("network protocol is unknown: sctp"),
+		},
+		"result code not success": {
+			protocol: "udp",
+			request:  []byte{0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 60},
+			response: []byte{0, 129, 0, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+			err:      errors.New("result code is not success: not authorized or refused"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+			client := newTestResponder(t, func(request []byte) []byte {
+				requests++
+				assert.Equal(t, testCase.request, request)
+				if testCase.dropFirstRequest && requests == 1 {
+					return nil
+				}
+				return testCase.response
+			})
+
+			const internalPort, requestedExternalPort = 1, 0
+			assignedPort, assignedLifetime, err := client.AddPortMapping(
+				context.Background(), net.IPv4(127, 0, 0, 1), testCase.protocol,
+				internalPort, requestedExternalPort, time.Minute)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.assignedPort, assignedPort)
+			assert.Equal(t, testCase.assignedLifetime, assignedLifetime)
+		})
+	}
+}
+
+func Test_Client_rpc_canceled(t *testing.T) {
+	t.Parallel()
+
+	client := newTestResponder(t, func(request []byte) []byte { return nil })
+	client.initialRetry = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.ExternalAddress(ctx, net.IPv4(127, 0, 0, 1))
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,65 @@
+package natpmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+var (
+	ErrNetworkProtocolUnknown = errors.New("network protocol is unknown")
+	ErrLifetimeTooLong        = errors.New("lifetime is too long")
+)
+
+// AddPortMapping requests the gateway to map the internal port to an
+// external port, as defined in section 3.3 of RFC 6886.
+// The protocol can be 'udp' or 'tcp'. The requested external port is
+// only a suggestion to the gateway and can be 0 to let it pick a port.
+// A zero lifetime deletes the mapping for the internal port.
+func (c *Client) AddPortMapping(ctx context.Context, gateway net.IP,
+	protocol string, internalPort, requestedExternalPort uint16,
+	lifetime time.Duration) (assignedExternalPort uint16,
+	assignedLifetime time.Duration, err error) {
+	const version = 0
+	var operationCode byte
+	switch protocol {
+	case constants.UDP:
+		operationCode = 1
+	case constants.TCP:
+		operationCode = 2 //nolint:gomnd
+	default:
+		return 0, 0, fmt.Errorf("%w: %s", ErrNetworkProtocolUnknown, protocol)
+	}
+
+	lifetimeSeconds := uint64(lifetime / time.Second)
+	if lifetimeSeconds > math.MaxUint32 {
+		return 0, 0, fmt.Errorf("%w: %s", ErrLifetimeTooLong, lifetime)
+	}
+
+	const requestSize = 12
+	request := make([]byte, requestSize)
+	request[0] = version
+	request[1] = operationCode
+	// Bytes 2 and 3 are reserved and must be zero.
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], requestedExternalPort)
+	binary.BigEndian.PutUint32(request[8:12], uint32(lifetimeSeconds))
+
+	const responseSize = 16
+	response, err := c.rpc(ctx, gateway, request, responseSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Bytes 4 to 8 are the seconds since start of epoch and bytes
+	// 8 to 10 are the internal port, which are both ignored.
+	assignedExternalPort = binary.BigEndian.Uint16(response[10:12])
+	assignedLifetime = time.Duration(binary.BigEndian.Uint32(response[12:16])) * time.Second
+	return assignedExternalPort, assignedLifetime, nil
+}
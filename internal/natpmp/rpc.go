@@ -0,0 +1,145 @@
+package natpmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+var (
+	ErrGatewayIPIsNil         = errors.New("gateway IP address is nil")
+	ErrConnectionTimeout      = errors.New("connection timeout")
+	ErrResponseSizeNotValid   = errors.New("response size is not valid")
+	ErrProtocolVersionUnknown = errors.New("protocol version is unknown")
+	ErrOperationCodeUnknown   = errors.New("operation code is unknown")
+	ErrResultCodeNotSuccess   = errors.New("result code is not success")
+)
+
+// rpc sends the request to the gateway and returns its response,
+// retransmitting the request with a doubling delay until a response
+// is received or the maximum number of tries is reached.
+func (c *Client) rpc(ctx context.Context, gateway net.IP,
+	request []byte, responseSize int) (response []byte, err error) {
+	if gateway == nil {
+		return nil, ErrGatewayIPIsNil
+	}
+
+	gatewayAddress := &net.UDPAddr{
+		IP:   gateway,
+		Port: int(c.serverPort),
+	}
+	connection, err := net.DialUDP("udp", nil, gatewayAddress)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial gateway: %w", err)
+	}
+	defer connection.Close()
+
+	// Close the connection on context cancellation
+	// to unblock any pending read.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = connection.Close()
+		case <-done:
+		}
+	}()
+
+	// Read one byte more than expected to detect larger responses.
+	buffer := make([]byte, responseSize+1)
+	retryDelay := c.initialRetry
+	for try := 0; try < c.maxRetries; try++ {
+		_, err = connection.Write(request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("cannot write to connection: %w", err)
+		}
+
+		err = connection.SetReadDeadline(time.Now().Add(retryDelay))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("cannot set read deadline: %w", err)
+		}
+
+		n, err := connection.Read(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				retryDelay *= 2
+				continue
+			}
+
+			return nil, fmt.Errorf("cannot read from connection: %w", err)
+		}
+
+		response = buffer[:n]
+		err = checkResponse(request, response, responseSize)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("%w: after %d tries", ErrConnectionTimeout, c.maxRetries)
+}
+
+func checkResponse(request, response []byte, expectedSize int) (err error) {
+	const minimumSize = 4
+	if len(response) < minimumSize {
+		return fmt.Errorf("%w: %d bytes is smaller than %d bytes",
+			ErrResponseSizeNotValid, len(response), minimumSize)
+	}
+
+	if response[0] != request[0] {
+		return fmt.Errorf("%w: %d", ErrProtocolVersionUnknown, response[0])
+	}
+
+	// The response operation code is the request
+	// operation code with its most significant bit set.
+	expectedOperationCode := request[1] | 0x80
+	if response[1] != expectedOperationCode {
+		return fmt.Errorf("%w: %d instead of %d",
+			ErrOperationCodeUnknown, response[1], expectedOperationCode)
+	}
+
+	resultCode := binary.BigEndian.Uint16(response[2:4])
+	if resultCode != 0 {
+		return fmt.Errorf("%w: %s", ErrResultCodeNotSuccess, resultCodeToString(resultCode))
+	}
+
+	if len(response) != expectedSize {
+		return fmt.Errorf("%w: %d bytes instead of %d bytes",
+			ErrResponseSizeNotValid, len(response), expectedSize)
+	}
+
+	return nil
+}
+
+func resultCodeToString(resultCode uint16) string {
+	switch resultCode {
+	case 1: //nolint:gomnd
+		return "unsupported version"
+	case 2: //nolint:gomnd
+		return "not authorized or refused"
+	case 3: //nolint:gomnd
+		return "network failure"
+	case 4: //nolint:gomnd
+		return "out of resources"
+	case 5: //nolint:gomnd
+		return "unsupported operation code"
+	default:
+		return fmt.Sprintf("unknown result code %d", resultCode)
+	}
+}
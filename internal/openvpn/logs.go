@@ -46,7 +46,7 @@ Your credentials might be wrong 🤨
 
 `
 		level = levelError
-	case strings.Contains(s, "TLS Error: TLS key negotiation failed to occur within 60 seconds (check your network connectivity)"): //nolint:lll
+	case isTLSErrorLine(s):
 		filtered = s + `
 🚒🚒🚒🚒🚒🚨🚨🚨🚨🚨🚨🚒🚒🚒🚒🚒
 That error usually happens because either:
@@ -68,3 +68,7 @@ That error usually happens because either:
 	filtered = constants.ColorOpenvpn().Sprintf(filtered)
 	return filtered, level
 }
+
+func isTLSErrorLine(s string) bool {
+	return strings.Contains(s, "TLS Error: TLS key negotiation failed to occur within 60 seconds (check your network connectivity)") //nolint:lll
+}
@@ -2,39 +2,61 @@ package openvpn
 
 import (
 	"context"
+	"errors"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/golibs/command"
 )
 
 type Runner struct {
-	settings settings.OpenVPN
-	starter  command.Starter
-	logger   Logger
+	settings       settings.OpenVPN
+	starter        command.Starter
+	logger         Logger
+	exitOnTLSError bool
 }
 
+// NewRunner creates an OpenVPN runner. If exitOnTLSError is true,
+// OpenVPN is stopped as soon as the TLS key negotiation fails,
+// instead of letting OpenVPN retry with the same server.
 func NewRunner(settings settings.OpenVPN, starter command.Starter,
-	logger Logger) *Runner {
+	logger Logger, exitOnTLSError bool) *Runner {
 	return &Runner{
-		starter:  starter,
-		logger:   logger,
-		settings: settings,
+		starter:        starter,
+		logger:         logger,
+		settings:       settings,
+		exitOnTLSError: exitOnTLSError,
 	}
 }
 
+var ErrTLSNegotiationFailed = errors.New("TLS key negotiation failed")
+
 func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struct{}) {
-	stdoutLines, stderrLines, waitError, err := start(ctx, r.starter, r.settings.Version, r.settings.Flags)
+	processCtx, processCancel := context.WithCancel(ctx)
+	defer processCancel()
+	stdoutLines, stderrLines, waitError, err := start(processCtx, r.starter, r.settings.Version, r.settings.Flags)
 	if err != nil {
 		errCh <- err
 		return
 	}
 
+	var tlsError chan struct{}
+	if r.exitOnTLSError {
+		tlsError = make(chan struct{}, 1)
+	}
+
 	streamCtx, streamCancel := context.WithCancel(context.Background())
 	streamDone := make(chan struct{})
 	go streamLines(streamCtx, streamDone, r.logger,
-		stdoutLines, stderrLines, ready)
+		stdoutLines, stderrLines, ready, tlsError)
 
 	select {
+	case <-tlsError:
+		processCancel()
+		<-waitError
+		close(waitError)
+		streamCancel()
+		<-streamDone
+		errCh <- ErrTLSNegotiationFailed
 	case <-ctx.Done():
 		<-waitError
 		close(waitError)
@@ -0,0 +1,15 @@
+package portforward
+
+import (
+	"context"
+
+	"github.com/qdm12/gluetun/internal/portforward/clients"
+)
+
+// setClientsPort sets the port forwarded as the listening port of
+// the torrent clients configured, retrying until it succeeds or
+// the context is canceled.
+func (l *Loop) setClientsPort(ctx context.Context, port uint16) {
+	updater := clients.NewUpdater(l.state.GetSettings(), l.client, l.logger)
+	updater.SetPort(ctx, port)
+}
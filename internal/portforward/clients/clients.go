@@ -0,0 +1,110 @@
+// Package clients sets the forwarded port as the listening
+// port of torrent clients, such as qBittorrent and Transmission.
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+var (
+	ErrAuthenticationFailed = errors.New("authentication failed")
+	ErrHTTPStatusCodeNotOK  = errors.New("HTTP status code not OK")
+	ErrRPCFailed            = errors.New("RPC call failed")
+)
+
+// PortSetter sets the listening port of a client.
+type PortSetter interface {
+	String() string
+	SetPort(ctx context.Context, port uint16) (err error)
+}
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+}
+
+// Updater sets the forwarded port on the clients configured,
+// retrying with an exponential backoff on failure.
+type Updater struct {
+	setters        []PortSetter
+	logger         Logger
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// NewUpdater creates an updater for the clients configured in
+// the port forwarding settings given.
+func NewUpdater(settings settings.PortForwarding, client *http.Client,
+	logger Logger) *Updater {
+	var setters []PortSetter
+	if *settings.QBittorrent.Address != "" {
+		setters = append(setters, NewQBittorrent(client, settings.QBittorrent))
+	}
+	if *settings.Transmission.Address != "" {
+		setters = append(setters, NewTransmission(client, settings.Transmission))
+	}
+
+	const (
+		initialBackoff = 5 * time.Second
+		maxBackoff     = 5 * time.Minute
+	)
+	return &Updater{
+		setters:        setters,
+		logger:         logger,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+	}
+}
+
+// SetPort sets the port on each client in parallel, and returns
+// once the port is set on all the clients or the context is canceled.
+func (u *Updater) SetPort(ctx context.Context, port uint16) {
+	wg := new(sync.WaitGroup)
+	for _, setter := range u.setters {
+		wg.Add(1)
+		go func(setter PortSetter) {
+			defer wg.Done()
+			u.setPort(ctx, setter, port)
+		}(setter)
+	}
+	wg.Wait()
+}
+
+func (u *Updater) setPort(ctx context.Context, setter PortSetter, port uint16) {
+	backoff := u.initialBackoff
+	for {
+		err := setter.SetPort(ctx, port)
+		if err == nil {
+			u.logger.Info("listening port of " + setter.String() +
+				" set to " + strconv.Itoa(int(port)))
+			return
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		u.logger.Warn("cannot set listening port of " + setter.String() +
+			": " + err.Error() + "; retrying in " + backoff.String())
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			if !timer.Stop() {
+				<-timer.C
+			}
+			return
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > u.maxBackoff {
+			backoff = u.maxBackoff
+		}
+	}
+}
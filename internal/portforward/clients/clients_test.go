@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringPtr(s string) *string { return &s }
+
+type testLogger struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (t *testLogger) Info(s string) { t.log("INFO " + s) }
+func (t *testLogger) Warn(s string) { t.log("WARN " + s) }
+
+func (t *testLogger) log(s string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lines = append(t.lines, s)
+}
+
+func Test_Updater_SetPort(t *testing.T) {
+	t.Parallel()
+
+	// The stand-in fails the first two requests.
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Transmission-Session-Id", "session")
+		_, _ = w.Write([]byte(`{"result":"success"}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	portForwarding := settings.PortForwarding{}
+	portForwarding.QBittorrent.Address = stringPtr("")
+	portForwarding.Transmission.Address = stringPtr(server.URL)
+	portForwarding.Transmission.User = stringPtr("")
+	portForwarding.Transmission.Password = stringPtr("")
+
+	logger := &testLogger{}
+	updater := NewUpdater(portForwarding, server.Client(), logger)
+	updater.initialBackoff = time.Millisecond
+	updater.maxBackoff = 2 * time.Millisecond
+
+	updater.SetPort(context.Background(), 5678)
+
+	expectedLines := []string{
+		"WARN cannot set listening port of Transmission: " +
+			"HTTP status code not OK: 503 Service Unavailable; retrying in 1ms",
+		"WARN cannot set listening port of Transmission: " +
+			"HTTP status code not OK: 503 Service Unavailable; retrying in 2ms",
+		"INFO listening port of Transmission set to 5678",
+	}
+	assert.Equal(t, expectedLines, logger.lines)
+}
+
+func Test_Updater_SetPort_canceled(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	portForwarding := settings.PortForwarding{}
+	portForwarding.QBittorrent.Address = stringPtr(server.URL)
+	portForwarding.QBittorrent.User = stringPtr("")
+	portForwarding.QBittorrent.Password = stringPtr("")
+	portForwarding.Transmission.Address = stringPtr("")
+
+	logger := &testLogger{}
+	updater := NewUpdater(portForwarding, server.Client(), logger)
+	updater.initialBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		updater.SetPort(ctx, 5678)
+	}()
+
+	// Wait for the first failure before canceling.
+	for {
+		logger.mutex.Lock()
+		failed := len(logger.lines) > 0
+		logger.mutex.Unlock()
+		if failed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	assert.Len(t, logger.lines, 1)
+	assert.True(t, strings.HasPrefix(logger.lines[0],
+		"WARN cannot set listening port of qBittorrent: "))
+}
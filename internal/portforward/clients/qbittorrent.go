@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// QBittorrent sets the listening port of a qBittorrent
+// client using its Web API.
+type QBittorrent struct {
+	client   *http.Client
+	address  string
+	user     string
+	password string
+}
+
+func NewQBittorrent(client *http.Client,
+	settings settings.PortForwardingClient) *QBittorrent {
+	return &QBittorrent{
+		client:   client,
+		address:  strings.TrimSuffix(*settings.Address, "/"),
+		user:     *settings.User,
+		password: *settings.Password,
+	}
+}
+
+func (q *QBittorrent) String() string { return "qBittorrent" }
+
+// SetPort logs in if a user is set, and sets the
+// listening port in the qBittorrent preferences.
+func (q *QBittorrent) SetPort(ctx context.Context, port uint16) (err error) {
+	cookies, err := q.login(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot login: %w", err)
+	}
+
+	values := url.Values{}
+	values.Set("json", fmt.Sprintf(`{"listen_port":%d}`, port))
+	response, err := q.postForm(ctx, "/api/v2/app/setPreferences", values, cookies)
+	if err != nil {
+		return fmt.Errorf("cannot set preferences: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot set preferences: %w: %s",
+			ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	return response.Body.Close()
+}
+
+// login logs in to the Web API and returns the session cookies
+// to use for subsequent requests. It does nothing if no user
+// is set, for clients allowing requests without authentication.
+func (q *QBittorrent) login(ctx context.Context) (cookies []*http.Cookie, err error) {
+	if q.user == "" {
+		return nil, nil
+	}
+
+	values := url.Values{}
+	values.Set("username", q.user)
+	values.Set("password", q.password)
+	response, err := q.postForm(ctx, "/api/v2/auth/login", values, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %w", err)
+	}
+
+	// The response status code is 200 even if the credentials
+	// are wrong, and only the body indicates the outcome.
+	if body := strings.TrimSpace(string(b)); body != "Ok." {
+		return nil, fmt.Errorf("%w: %s", ErrAuthenticationFailed, body)
+	}
+
+	return response.Cookies(), response.Body.Close()
+}
+
+func (q *QBittorrent) postForm(ctx context.Context, path string,
+	values url.Values, cookies []*http.Cookie) (response *http.Response, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		q.address+path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// qBittorrent rejects requests with a Referer or Origin
+	// header not matching its address, so set it to its address.
+	request.Header.Set("Referer", q.address)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+
+	return q.client.Do(request)
+}
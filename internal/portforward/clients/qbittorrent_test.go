@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQBittorrentStandIn(t *testing.T, loginBody string,
+	preferencesStatus int) (server *httptest.Server, preferences *string) {
+	t.Helper()
+
+	preferences = new(string)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.FormValue("username") == "user" && r.FormValue("password") == "password" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+		}
+		_, _ = w.Write([]byte(loginBody))
+	})
+	mux.HandleFunc("/api/v2/app/setPreferences", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		cookie, err := r.Cookie("SID")
+		if err != nil || cookie.Value != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		*preferences = r.FormValue("json")
+		w.WriteHeader(preferencesStatus)
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, preferences
+}
+
+func Test_QBittorrent_SetPort(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		password          string
+		loginBody         string
+		preferencesStatus int
+		preferences       string
+		err               error
+	}{
+		"success": {
+			password:          "password",
+			loginBody:         "Ok.",
+			preferencesStatus: http.StatusOK,
+			preferences:       `{"listen_port":5678}`,
+		},
+		"wrong credentials": {
+			password:  "wrong",
+			loginBody: "Fails.",
+			err:       errors.New("cannot login: authentication failed: Fails."),
+		},
+		"preferences error": {
+			password:          "password",
+			loginBody:         "Ok.",
+			preferencesStatus: http.StatusBadRequest,
+			preferences:       `{"listen_port":5678}`,
+			err: errors.New("cannot set preferences: " +
+				"HTTP status code not OK: 400 Bad Request"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server, preferences := newQBittorrentStandIn(t,
+				testCase.loginBody, testCase.preferencesStatus)
+			qbittorrent := NewQBittorrent(server.Client(), settings.PortForwardingClient{
+				Address:  stringPtr(server.URL + "/"),
+				User:     stringPtr("user"),
+				Password: stringPtr(testCase.password),
+			})
+
+			err := qbittorrent.SetPort(context.Background(), 5678)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.preferences, *preferences)
+		})
+	}
+}
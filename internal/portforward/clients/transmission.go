@@ -0,0 +1,112 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// Transmission sets the listening port of a
+// Transmission client using its RPC interface.
+type Transmission struct {
+	client    *http.Client
+	url       string
+	user      string
+	password  string
+	sessionID string
+}
+
+func NewTransmission(client *http.Client,
+	settings settings.PortForwardingClient) *Transmission {
+	return &Transmission{
+		client:   client,
+		url:      strings.TrimSuffix(*settings.Address, "/") + "/transmission/rpc",
+		user:     *settings.User,
+		password: *settings.Password,
+	}
+}
+
+func (t *Transmission) String() string { return "Transmission" }
+
+type transmissionRequest struct {
+	Method    string      `json:"method"`
+	Arguments interface{} `json:"arguments"`
+}
+
+type transmissionResponse struct {
+	Result string `json:"result"`
+}
+
+// SetPort sets the peer port in the Transmission session settings.
+func (t *Transmission) SetPort(ctx context.Context, port uint16) (err error) {
+	body, err := json.Marshal(transmissionRequest{
+		Method: "session-set",
+		Arguments: struct {
+			PeerPort uint16 `json:"peer-port"`
+		}{PeerPort: port},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	response, err := t.post(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode == http.StatusConflict {
+		// The session ID is missing or expired, and the response
+		// contains a new session ID to retry the request with.
+		_ = response.Body.Close()
+		t.sessionID = response.Header.Get("X-Transmission-Session-Id")
+		response, err = t.post(ctx, body)
+		if err != nil {
+			return err
+		}
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, response.Status)
+	default:
+		return fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	var data transmissionResponse
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&data)
+	if err != nil {
+		return fmt.Errorf("cannot decode response: %w", err)
+	}
+
+	if data.Result != "success" {
+		return fmt.Errorf("%w: %s", ErrRPCFailed, data.Result)
+	}
+
+	return response.Body.Close()
+}
+
+func (t *Transmission) post(ctx context.Context, body []byte) (
+	response *http.Response, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if t.sessionID != "" {
+		request.Header.Set("X-Transmission-Session-Id", t.sessionID)
+	}
+	if t.user != "" {
+		request.SetBasicAuth(t.user, t.password)
+	}
+
+	return t.client.Do(request)
+}
@@ -0,0 +1,98 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Transmission_SetPort(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		password string
+		result   string
+		peerPort uint16
+		err      error
+	}{
+		"success": {
+			password: "password",
+			result:   "success",
+			peerPort: 5678,
+		},
+		"wrong credentials": {
+			password: "wrong",
+			err:      errors.New("authentication failed: 401 Unauthorized"),
+		},
+		"RPC error": {
+			password: "password",
+			result:   "invalid argument",
+			peerPort: 5678,
+			err:      errors.New("RPC call failed: invalid argument"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var peerPort uint16
+			const sessionID = "session"
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/transmission/rpc", r.URL.Path)
+
+				user, password, ok := r.BasicAuth()
+				if !ok || user != "user" || password != "password" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if r.Header.Get("X-Transmission-Session-Id") != sessionID {
+					w.Header().Set("X-Transmission-Session-Id", sessionID)
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+
+				var request struct {
+					Method    string `json:"method"`
+					Arguments struct {
+						PeerPort uint16 `json:"peer-port"`
+					} `json:"arguments"`
+				}
+				err := json.NewDecoder(r.Body).Decode(&request)
+				require.NoError(t, err)
+				assert.Equal(t, "session-set", request.Method)
+				peerPort = request.Arguments.PeerPort
+
+				_, _ = w.Write([]byte(`{"arguments":{},"result":"` + testCase.result + `"}`))
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			t.Cleanup(server.Close)
+
+			transmission := NewTransmission(server.Client(), settings.PortForwardingClient{
+				Address:  stringPtr(server.URL),
+				User:     stringPtr("user"),
+				Password: stringPtr(testCase.password),
+			})
+
+			err := transmission.SetPort(context.Background(), 5678)
+
+			if testCase.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.peerPort, peerPort)
+		})
+	}
+}
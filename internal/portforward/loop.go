@@ -7,6 +7,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
@@ -44,14 +45,15 @@ const defaultBackoffTime = 5 * time.Second
 
 func NewLoop(settings settings.PortForwarding,
 	client *http.Client, portAllower firewall.PortAllower,
-	logger Logger) *Loop {
+	logger Logger, publisher events.Publisher) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
-	state := state.New(statusManager, settings)
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped,
+		"port forwarding", publisher)
+	state := state.New(statusManager, settings, publisher)
 
 	return &Loop{
 		statusManager: statusManager,
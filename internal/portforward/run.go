@@ -83,6 +83,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 				l.state.SetPortForwarded(port)
 				l.firewallAllowPort(ctx)
 				l.writePortForwardedFile(port)
+				go l.setClientsPort(pfCtx, port)
 			case err := <-errorCh:
 				pfCancel()
 				close(errorCh)
@@ -1,5 +1,7 @@
 package state
 
+import "github.com/qdm12/gluetun/internal/events"
+
 type PortForwardedGetterSetter interface {
 	PortForwardedGetter
 	SetPortForwarded(port uint16)
@@ -18,9 +20,14 @@ func (s *State) GetPortForwarded() (port uint16) {
 }
 
 // SetPortForwarded is only used from within the OpenVPN loop
-// to set the port forwarded.
+// to set the port forwarded. It publishes a port forwarded
+// event if the port changed.
 func (s *State) SetPortForwarded(port uint16) {
 	s.portForwardedMu.Lock()
 	defer s.portForwardedMu.Unlock()
+	if s.portForwarded == port {
+		return
+	}
 	s.portForwarded = port
+	s.publisher.Publish("port forwarding", events.TypePortForwarded, port)
 }
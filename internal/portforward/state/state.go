@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 )
 
@@ -16,10 +17,11 @@ type Manager interface {
 }
 
 func New(statusApplier loopstate.Applier,
-	settings settings.PortForwarding) *State {
+	settings settings.PortForwarding, publisher events.Publisher) *State {
 	return &State{
 		statusApplier: statusApplier,
 		settings:      settings,
+		publisher:     publisher,
 	}
 }
 
@@ -34,4 +36,6 @@ type State struct {
 
 	startData   StartData
 	startDataMu sync.RWMutex
+
+	publisher events.Publisher
 }
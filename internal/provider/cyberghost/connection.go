@@ -33,5 +33,5 @@ func (c *Cyberghost) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, c.randSource)
+	return utils.PickConnection(connections, selection, c.randSource, c.prober, c.blocklist)
 }
@@ -11,13 +11,18 @@ import (
 type Cyberghost struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	utils.NoPortForwarder
 }
 
-func New(servers []models.Server, randSource rand.Source) *Cyberghost {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *Cyberghost {
 	return &Cyberghost{
 		servers:         servers,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.Cyberghost),
 	}
 }
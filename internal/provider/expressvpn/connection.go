@@ -30,7 +30,7 @@ func (p *Provider) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, p.randSource)
+	return utils.PickConnection(connections, selection, p.randSource, p.prober, p.blocklist)
 }
 
 func getPort(selection settings.ServerSelection) (port uint16) {
@@ -103,7 +103,7 @@ func Test_Expressvpn_filterServers(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			m := New(testCase.servers, randSource)
+			m := New(testCase.servers, randSource, nil, nil)
 
 			servers, err := m.filterServers(testCase.selection)
 
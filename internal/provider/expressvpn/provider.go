@@ -11,13 +11,18 @@ import (
 type Provider struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	utils.NoPortForwarder
 }
 
-func New(servers []models.Server, randSource rand.Source) *Provider {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *Provider {
 	return &Provider{
 		servers:         servers,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.Expressvpn),
 	}
 }
@@ -33,5 +33,5 @@ func (f *Fastestvpn) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, f.randSource)
+	return utils.PickConnection(connections, selection, f.randSource, f.prober, f.blocklist)
 }
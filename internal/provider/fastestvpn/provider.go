@@ -11,13 +11,18 @@ import (
 type Fastestvpn struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	utils.NoPortForwarder
 }
 
-func New(servers []models.Server, randSource rand.Source) *Fastestvpn {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *Fastestvpn {
 	return &Fastestvpn{
 		servers:         servers,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.Fastestvpn),
 	}
 }
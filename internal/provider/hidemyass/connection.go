@@ -38,5 +38,5 @@ func (h *HideMyAss) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, h.randSource)
+	return utils.PickConnection(connections, selection, h.randSource, h.prober, h.blocklist)
 }
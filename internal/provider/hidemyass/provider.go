@@ -11,13 +11,18 @@ import (
 type HideMyAss struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	utils.NoPortForwarder
 }
 
-func New(servers []models.Server, randSource rand.Source) *HideMyAss {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *HideMyAss {
 	return &HideMyAss{
 		servers:         servers,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.HideMyAss),
 	}
 }
@@ -31,5 +31,5 @@ func (i *Ipvanish) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, i.randSource)
+	return utils.PickConnection(connections, selection, i.randSource, i.prober, i.blocklist)
 }
@@ -11,13 +11,18 @@ import (
 type Ipvanish struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	utils.NoPortForwarder
 }
 
-func New(servers []models.Server, randSource rand.Source) *Ipvanish {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *Ipvanish {
 	return &Ipvanish{
 		servers:         servers,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		NoPortForwarder: utils.NewNoPortForwarding(providers.Ipvanish),
 	}
 }
@@ -31,7 +31,7 @@ func (i *Ivpn) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, i.randSource)
+	return utils.PickConnection(connections, selection, i.randSource, i.prober, i.blocklist)
 }
 
 func getPort(selection settings.ServerSelection) (port uint16) {
@@ -83,7 +83,7 @@ func Test_Ivpn_GetConnection(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			m := New(testCase.servers, randSource)
+			m := New(testCase.servers, randSource, nil, nil)
 
 			connection, err := m.GetConnection(testCase.selection)
 
@@ -117,7 +117,7 @@ func Test_Ivpn_filterServers(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			m := New(testCase.servers, randSource)
+			m := New(testCase.servers, randSource, nil, nil)
 
 			servers, err := m.filterServers(testCase.selection)
 
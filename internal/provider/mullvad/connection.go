@@ -34,7 +34,7 @@ func (m *Mullvad) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, m.randSource)
+	return utils.PickConnection(connections, selection, m.randSource, m.prober, m.blocklist)
 }
 
 func getPort(selection settings.ServerSelection) (port uint16) {
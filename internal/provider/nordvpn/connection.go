@@ -34,5 +34,5 @@ func (n *Nordvpn) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, n.randSource)
+	return utils.PickConnection(connections, selection, n.randSource, n.prober, n.blocklist)
 }
@@ -33,5 +33,5 @@ func (p *Perfectprivacy) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, p.randSource)
+	return utils.PickConnection(connections, selection, p.randSource, p.prober, p.blocklist)
 }
@@ -31,5 +31,5 @@ func (p *Privado) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, p.randSource)
+	return utils.PickConnection(connections, selection, p.randSource, p.prober, p.blocklist)
 }
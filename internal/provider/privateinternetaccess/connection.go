@@ -38,5 +38,5 @@ func (p *PIA) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, p.randSource)
+	return utils.PickConnection(connections, selection, p.randSource, p.prober, p.blocklist)
 }
@@ -6,11 +6,14 @@ import (
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
 type PIA struct {
 	servers    []models.Server
 	randSource rand.Source
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
 	timeNow    func() time.Time
 	// Port forwarding
 	portForwardPath string
@@ -18,12 +21,15 @@ type PIA struct {
 }
 
 func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist,
 	timeNow func() time.Time) *PIA {
 	const jsonPortForwardPath = "/gluetun/piaportforward.json"
 	return &PIA{
 		servers:         servers,
 		timeNow:         timeNow,
 		randSource:      randSource,
+		prober:          prober,
+		blocklist:       blocklist,
 		portForwardPath: jsonPortForwardPath,
 		authFilePath:    constants.OpenVPNAuthConf,
 	}
@@ -37,5 +37,5 @@ func (p *Privatevpn) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, p.randSource)
+	return utils.PickConnection(connections, selection, p.randSource, p.prober, p.blocklist)
 }
@@ -18,7 +18,8 @@ func (p *Protonvpn) filterServers(selection settings.ServerSelection) (
 			utils.FilterByPossibilities(server.City, selection.Cities),
 			utils.FilterByPossibilities(server.Hostname, selection.Hostnames),
 			utils.FilterByPossibilities(server.ServerName, selection.Names),
-			*selection.FreeOnly && !strings.Contains(strings.ToLower(server.ServerName), "free"):
+			*selection.FreeOnly && !strings.Contains(strings.ToLower(server.ServerName), "free"),
+			*selection.PortForwardOnly && !server.PortForward:
 		default:
 			servers = append(servers, server)
 		}
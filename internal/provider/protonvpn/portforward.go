@@ -0,0 +1,97 @@
+package protonvpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+var (
+	ErrGatewayIPIsNil       = errors.New("gateway IP address is nil")
+	ErrExternalPortChanged  = errors.New("external port changed")
+	ErrExternalPortMismatch = errors.New("external ports mismatch")
+)
+
+const (
+	// portMappingInternalPort is the internal port ProtonVPN expects
+	// in mapping requests, it does not have to match the local port.
+	portMappingInternalPort = 1
+	portMappingLifetime     = 60 * time.Second
+)
+
+// PortForward obtains a VPN server side port forwarded from
+// ProtonVPN using NAT-PMP with the VPN gateway.
+func (p *Protonvpn) PortForward(ctx context.Context, client *http.Client,
+	logger utils.Logger, gateway net.IP, serverName string) (
+	port uint16, err error) {
+	if gateway == nil {
+		return 0, ErrGatewayIPIsNil
+	}
+
+	externalIPv4Address, err := p.natpmp.ExternalAddress(ctx, gateway)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get external IPv4 address: %w", err)
+	}
+	logger.Info("gateway external IPv4 address is " + externalIPv4Address.String())
+
+	return p.addPortMappings(ctx, gateway, 0)
+}
+
+// KeepPortForward renews the port mappings before their lifetime expires,
+// and returns an error if the gateway fails to keep the same external port.
+func (p *Protonvpn) KeepPortForward(ctx context.Context, client *http.Client,
+	port uint16, gateway net.IP, serverName string) (err error) {
+	const refreshPeriod = 45 * time.Second
+	// Timer behaving as a ticker
+	timer := time.NewTimer(refreshPeriod)
+	for {
+		select {
+		case <-ctx.Done():
+			if !timer.Stop() {
+				<-timer.C
+			}
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		assignedPort, err := p.addPortMappings(ctx, gateway, port)
+		if err != nil {
+			return err
+		} else if assignedPort != port {
+			return fmt.Errorf("%w: from %d to %d",
+				ErrExternalPortChanged, port, assignedPort)
+		}
+		timer.Reset(refreshPeriod)
+	}
+}
+
+// addPortMappings maps the same external port for both UDP and TCP,
+// and returns the external port assigned by the gateway.
+// The requested external port can be 0 to let the gateway pick one.
+func (p *Protonvpn) addPortMappings(ctx context.Context, gateway net.IP,
+	requestedExternalPort uint16) (externalPort uint16, err error) {
+	for _, protocol := range []string{constants.UDP, constants.TCP} {
+		assignedPort, _, err := p.natpmp.AddPortMapping(ctx, gateway, protocol,
+			portMappingInternalPort, requestedExternalPort, portMappingLifetime)
+		if err != nil {
+			return 0, fmt.Errorf("cannot add %s port mapping: %w", protocol, err)
+		}
+
+		if externalPort != 0 && assignedPort != externalPort {
+			return 0, fmt.Errorf("%w: %s port %d and %s port %d",
+				ErrExternalPortMismatch, constants.UDP, externalPort,
+				protocol, assignedPort)
+		}
+		externalPort = assignedPort
+		// Request the same external port for the next protocol.
+		requestedExternalPort = assignedPort
+	}
+
+	return externalPort, nil
+}
@@ -3,21 +3,27 @@ package protonvpn
 import (
 	"math/rand"
 
-	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/natpmp"
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
 type Protonvpn struct {
 	servers    []models.Server
 	randSource rand.Source
-	utils.NoPortForwarder
+	prober     utils.LatencyProber
+	blocklist  utils.Blocklist
+	// Port forwarding
+	natpmp *natpmp.Client
 }
 
-func New(servers []models.Server, randSource rand.Source) *Protonvpn {
+func New(servers []models.Server, randSource rand.Source,
+	prober utils.LatencyProber, blocklist utils.Blocklist) *Protonvpn {
 	return &Protonvpn{
-		servers:         servers,
-		randSource:      randSource,
-		NoPortForwarder: utils.NewNoPortForwarding(providers.Protonvpn),
+		servers:    servers,
+		randSource: randSource,
+		prober:     prober,
+		blocklist:  blocklist,
+		natpmp:     natpmp.New(),
 	}
 }
@@ -50,51 +50,56 @@ type PortForwarder interface {
 		port uint16, gateway net.IP, serverName string) (err error)
 }
 
-func New(provider string, allServers models.AllServers, timeNow func() time.Time) Provider {
+// New creates the provider given. The latency prober can be nil,
+// in which case connections are picked randomly. The blocklist can
+// be nil, in which case no server is excluded from the selection.
+func New(provider string, allServers models.AllServers,
+	prober utils.LatencyProber, blocklist utils.Blocklist,
+	timeNow func() time.Time) Provider {
 	randSource := rand.NewSource(timeNow().UnixNano())
 	switch provider {
 	case providers.Custom:
 		return custom.New()
 	case providers.Cyberghost:
-		return cyberghost.New(allServers.Cyberghost.Servers, randSource)
+		return cyberghost.New(allServers.Cyberghost.Servers, randSource, prober, blocklist)
 	case providers.Expressvpn:
-		return expressvpn.New(allServers.Expressvpn.Servers, randSource)
+		return expressvpn.New(allServers.Expressvpn.Servers, randSource, prober, blocklist)
 	case providers.Fastestvpn:
-		return fastestvpn.New(allServers.Fastestvpn.Servers, randSource)
+		return fastestvpn.New(allServers.Fastestvpn.Servers, randSource, prober, blocklist)
 	case providers.HideMyAss:
-		return hidemyass.New(allServers.HideMyAss.Servers, randSource)
+		return hidemyass.New(allServers.HideMyAss.Servers, randSource, prober, blocklist)
 	case providers.Ipvanish:
-		return ipvanish.New(allServers.Ipvanish.Servers, randSource)
+		return ipvanish.New(allServers.Ipvanish.Servers, randSource, prober, blocklist)
 	case providers.Ivpn:
-		return ivpn.New(allServers.Ivpn.Servers, randSource)
+		return ivpn.New(allServers.Ivpn.Servers, randSource, prober, blocklist)
 	case providers.Mullvad:
-		return mullvad.New(allServers.Mullvad.Servers, randSource)
+		return mullvad.New(allServers.Mullvad.Servers, randSource, prober, blocklist)
 	case providers.Nordvpn:
-		return nordvpn.New(allServers.Nordvpn.Servers, randSource)
+		return nordvpn.New(allServers.Nordvpn.Servers, randSource, prober, blocklist)
 	case providers.Perfectprivacy:
-		return perfectprivacy.New(allServers.Perfectprivacy.Servers, randSource)
+		return perfectprivacy.New(allServers.Perfectprivacy.Servers, randSource, prober, blocklist)
 	case providers.Privado:
-		return privado.New(allServers.Privado.Servers, randSource)
+		return privado.New(allServers.Privado.Servers, randSource, prober, blocklist)
 	case providers.PrivateInternetAccess:
-		return privateinternetaccess.New(allServers.Pia.Servers, randSource, timeNow)
+		return privateinternetaccess.New(allServers.Pia.Servers, randSource, prober, blocklist, timeNow)
 	case providers.Privatevpn:
-		return privatevpn.New(allServers.Privatevpn.Servers, randSource)
+		return privatevpn.New(allServers.Privatevpn.Servers, randSource, prober, blocklist)
 	case providers.Protonvpn:
-		return protonvpn.New(allServers.Protonvpn.Servers, randSource)
+		return protonvpn.New(allServers.Protonvpn.Servers, randSource, prober, blocklist)
 	case providers.Purevpn:
-		return purevpn.New(allServers.Purevpn.Servers, randSource)
+		return purevpn.New(allServers.Purevpn.Servers, randSource, prober, blocklist)
 	case providers.Surfshark:
-		return surfshark.New(allServers.Surfshark.Servers, randSource)
+		return surfshark.New(allServers.Surfshark.Servers, randSource, prober, blocklist)
 	case providers.Torguard:
-		return torguard.New(allServers.Torguard.Servers, randSource)
+		return torguard.New(allServers.Torguard.Servers, randSource, prober, blocklist)
 	case providers.VPNUnlimited:
-		return vpnunlimited.New(allServers.VPNUnlimited.Servers, randSource)
+		return vpnunlimited.New(allServers.VPNUnlimited.Servers, randSource, prober, blocklist)
 	case providers.Vyprvpn:
-		return vyprvpn.New(allServers.Vyprvpn.Servers, randSource)
+		return vyprvpn.New(allServers.Vyprvpn.Servers, randSource, prober, blocklist)
 	case providers.Wevpn:
-		return wevpn.New(allServers.Wevpn.Servers, randSource)
+		return wevpn.New(allServers.Wevpn.Servers, randSource, prober, blocklist)
 	case providers.Windscribe:
-		return windscribe.New(allServers.Windscribe.Servers, randSource)
+		return windscribe.New(allServers.Windscribe.Servers, randSource, prober, blocklist)
 	default:
 		panic("provider " + provider + " is unknown") // should never occur
 	}
@@ -34,5 +34,5 @@ func (s *Surfshark) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, s.randSource)
+	return utils.PickConnection(connections, selection, s.randSource, s.prober, s.blocklist)
 }
@@ -129,7 +129,7 @@ func Test_Surfshark_filterServers(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			s := New(testCase.servers, randSource)
+			s := New(testCase.servers, randSource, nil, nil)
 
 			servers, err := s.filterServers(testCase.selection)
 
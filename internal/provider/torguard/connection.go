@@ -37,5 +37,5 @@ func (t *Torguard) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, t.randSource)
+	return utils.PickConnection(connections, selection, t.randSource, t.prober, t.blocklist)
 }
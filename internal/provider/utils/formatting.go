@@ -114,6 +114,10 @@ func NoServerFoundError(selection settings.ServerSelection) (err error) {
 		messageParts = append(messageParts, "free tier only")
 	}
 
+	if *selection.PortForwardOnly {
+		messageParts = append(messageParts, "port forwarding only")
+	}
+
 	message := "for " + strings.Join(messageParts, "; ")
 
 	return fmt.Errorf("%w: %s", ErrNoServerFound, message)
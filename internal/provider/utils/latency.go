@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// LatencyProber measures the latency to each of the connections given.
+// The latency returned is 0 for a connection which cannot be reached.
+type LatencyProber interface {
+	ProbeLatencies(connections []models.Connection) (latencies []time.Duration)
+}
+
+// latencySampleSize is the maximum number of connections to probe,
+// to bound the time spent probing before connecting.
+const latencySampleSize = 10
+
+// pickLatencyConnection probes a random sample of the connections
+// and picks one of them according to the strategy given.
+// It falls back on picking a random connection if none of the
+// connections of the sample can be reached.
+func pickLatencyConnection(connections []models.Connection, strategy string,
+	randSource rand.Source, prober LatencyProber) models.Connection {
+	generator := rand.New(randSource) //nolint:gosec
+
+	sampleSize := latencySampleSize
+	if len(connections) < sampleSize {
+		sampleSize = len(connections)
+	}
+	sample := make([]models.Connection, sampleSize)
+	for i, index := range generator.Perm(len(connections))[:sampleSize] {
+		sample[i] = connections[index]
+	}
+
+	latencies := prober.ProbeLatencies(sample)
+
+	var index int
+	if strategy == constants.SelectionWeighted {
+		index = pickWeightedIndex(latencies, generator)
+	} else {
+		index = pickLowestIndex(latencies)
+	}
+
+	if index == -1 {
+		return pickRandomConnection(connections, randSource)
+	}
+	return sample[index]
+}
+
+// pickLowestIndex returns the index of the lowest non zero latency,
+// or -1 if all latencies are zero.
+func pickLowestIndex(latencies []time.Duration) (index int) {
+	index = -1
+	for i, latency := range latencies {
+		if latency == 0 {
+			continue
+		}
+		if index == -1 || latency < latencies[index] {
+			index = i
+		}
+	}
+	return index
+}
+
+// pickWeightedIndex returns the index of a random non zero latency,
+// where the probability to pick a latency is inversely proportional
+// to its value. It returns -1 if all latencies are zero.
+func pickWeightedIndex(latencies []time.Duration,
+	generator *rand.Rand) (index int) {
+	weights := make([]float64, len(latencies))
+	var total float64
+	for i, latency := range latencies {
+		if latency == 0 {
+			continue
+		}
+		weights[i] = 1 / latency.Seconds()
+		total += weights[i]
+	}
+
+	if total == 0 {
+		return -1
+	}
+
+	threshold := generator.Float64() * total
+	index = -1
+	for i, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+		index = i
+		threshold -= weight
+		if threshold < 0 {
+			break
+		}
+	}
+	return index
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProber struct {
+	latencies map[uint16]time.Duration // port to latency
+}
+
+func (p *testProber) ProbeLatencies(connections []models.Connection) (
+	latencies []time.Duration) {
+	latencies = make([]time.Duration, len(connections))
+	for i, connection := range connections {
+		latencies[i] = p.latencies[connection.Port]
+	}
+	return latencies
+}
+
+func Test_pickLatencyConnection(t *testing.T) {
+	t.Parallel()
+
+	connections := []models.Connection{
+		{Port: 1}, {Port: 2}, {Port: 3},
+	}
+
+	testCases := map[string]struct {
+		strategy   string
+		latencies  map[uint16]time.Duration
+		connection models.Connection
+	}{
+		"lowest latency": {
+			strategy: constants.SelectionLowestLatency,
+			latencies: map[uint16]time.Duration{
+				1: 30 * time.Millisecond,
+				2: 10 * time.Millisecond,
+				3: 20 * time.Millisecond,
+			},
+			connection: models.Connection{Port: 2},
+		},
+		"lowest latency ignores unreachable": {
+			strategy: constants.SelectionLowestLatency,
+			latencies: map[uint16]time.Duration{
+				1: 30 * time.Millisecond,
+				3: 20 * time.Millisecond,
+			},
+			connection: models.Connection{Port: 3},
+		},
+		"weighted with single reachable": {
+			strategy: constants.SelectionWeighted,
+			latencies: map[uint16]time.Duration{
+				1: 30 * time.Millisecond,
+			},
+			connection: models.Connection{Port: 1},
+		},
+		"none reachable": {
+			strategy:   constants.SelectionLowestLatency,
+			connection: models.Connection{Port: 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prober := &testProber{latencies: testCase.latencies}
+			randSource := rand.NewSource(0)
+
+			connection := pickLatencyConnection(connections,
+				testCase.strategy, randSource, prober)
+
+			assert.Equal(t, testCase.connection, connection)
+		})
+	}
+}
+
+func Test_pickWeightedIndex(t *testing.T) {
+	t.Parallel()
+
+	latencies := []time.Duration{
+		10 * time.Millisecond,
+		0,
+		90 * time.Millisecond,
+	}
+	generator := rand.New(rand.NewSource(0)) //nolint:gosec
+
+	counts := make([]int, len(latencies))
+	const picks = 1000
+	for i := 0; i < picks; i++ {
+		index := pickWeightedIndex(latencies, generator)
+		counts[index]++
+	}
+
+	assert.Zero(t, counts[1])
+	// The 10ms latency should be picked about 9 times more
+	// often than the 90ms latency.
+	assert.InDelta(t, 900, counts[0], 50)
+	assert.InDelta(t, 100, counts[2], 50)
+
+	index := pickWeightedIndex([]time.Duration{0, 0}, generator)
+	assert.Equal(t, -1, index)
+}
@@ -11,20 +11,45 @@ import (
 	"github.com/qdm12/gluetun/internal/models"
 )
 
+// Blocklist filters out the connections to the servers
+// temporarily excluded from the server selection.
+type Blocklist interface {
+	FilterConnections(connections []models.Connection) (filtered []models.Connection)
+}
+
 // PickConnection picks a connection from a pool of connections.
 // If the VPN protocol is Wireguard and the target IP is set,
 // it finds the connection corresponding to this target IP.
-// Otherwise, it picks a random connection from the pool of connections
-// and sets the target IP address as the IP if this one is set.
+// Otherwise, it picks a connection from the pool of connections using
+// the selection strategy, and sets the target IP address as the IP if
+// this one is set. The latency prober can be nil, in which case the
+// connection is picked randomly. The blocklist can be nil, and is
+// ignored if it would exclude all the connections.
 func PickConnection(connections []models.Connection,
-	selection settings.ServerSelection, randSource rand.Source) (
+	selection settings.ServerSelection, randSource rand.Source,
+	prober LatencyProber, blocklist Blocklist) (
 	connection models.Connection, err error) {
 	if len(selection.TargetIP) > 0 && selection.VPN == constants.Wireguard {
 		// we need the right public key
 		return getTargetIPConnection(connections, selection.TargetIP)
 	}
 
-	connection = pickRandomConnection(connections, randSource)
+	if blocklist != nil {
+		filtered := blocklist.FilterConnections(connections)
+		if len(filtered) > 0 {
+			connections = filtered
+		}
+	}
+
+	switch {
+	case len(selection.TargetIP) > 0, prober == nil,
+		selection.Strategy == constants.SelectionRandom:
+		connection = pickRandomConnection(connections, randSource)
+	default:
+		connection = pickLatencyConnection(connections,
+			selection.Strategy, randSource, prober)
+	}
+
 	if len(selection.TargetIP) > 0 {
 		connection.IP = selection.TargetIP
 	}
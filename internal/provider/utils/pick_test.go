@@ -2,12 +2,84 @@ package utils
 
 import (
 	"math/rand"
+	"net"
 	"testing"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+type testBlocklist struct {
+	blockedIPs []net.IP
+}
+
+func (b *testBlocklist) FilterConnections(connections []models.Connection) (
+	filtered []models.Connection) {
+	for _, connection := range connections {
+		blocked := false
+		for _, ip := range b.blockedIPs {
+			if ip.Equal(connection.IP) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			filtered = append(filtered, connection)
+		}
+	}
+	return filtered
+}
+
+func Test_PickConnection_blocklist(t *testing.T) {
+	t.Parallel()
+
+	connections := []models.Connection{
+		{IP: net.IP{1, 1, 1, 1}},
+		{IP: net.IP{2, 2, 2, 2}},
+	}
+	selection := settings.ServerSelection{
+		VPN:      constants.OpenVPN,
+		Strategy: constants.SelectionRandom,
+	}
+
+	testCases := map[string]struct {
+		blocklist  Blocklist
+		connection models.Connection
+	}{
+		"no blocklist": {
+			connection: models.Connection{IP: net.IP{1, 1, 1, 1}},
+		},
+		"blocked connection excluded": {
+			blocklist:  &testBlocklist{blockedIPs: []net.IP{{1, 1, 1, 1}}},
+			connection: models.Connection{IP: net.IP{2, 2, 2, 2}},
+		},
+		"blocklist ignored if all connections are blocked": {
+			blocklist: &testBlocklist{blockedIPs: []net.IP{
+				{1, 1, 1, 1}, {2, 2, 2, 2},
+			}},
+			connection: models.Connection{IP: net.IP{1, 1, 1, 1}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			randSource := rand.NewSource(2)
+
+			connection, err := PickConnection(connections, selection,
+				randSource, nil, testCase.blocklist)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.connection, connection)
+		})
+	}
+}
+
 func Test_pickRandomConnection(t *testing.T) {
 	t.Parallel()
 	connections := []models.Connection{
@@ -30,5 +30,5 @@ func (v *Vyprvpn) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, v.randSource)
+	return utils.PickConnection(connections, selection, v.randSource, v.prober, v.blocklist)
 }
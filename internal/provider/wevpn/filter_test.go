@@ -88,7 +88,7 @@ func Test_Wevpn_filterServers(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			w := New(testCase.servers, randSource)
+			w := New(testCase.servers, randSource, nil, nil)
 
 			servers, err := w.filterServers(testCase.selection)
 
@@ -31,7 +31,7 @@ func (w *Windscribe) GetConnection(selection settings.ServerSelection) (
 		}
 	}
 
-	return utils.PickConnection(connections, selection, w.randSource)
+	return utils.PickConnection(connections, selection, w.randSource, w.prober, w.blocklist)
 }
 
 func getPort(selection settings.ServerSelection) (port uint16) {
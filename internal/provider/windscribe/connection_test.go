@@ -82,7 +82,7 @@ func Test_Windscribe_GetConnection(t *testing.T) {
 
 			randSource := rand.NewSource(0)
 
-			m := New(testCase.servers, randSource)
+			m := New(testCase.servers, randSource, nil, nil)
 
 			connection, err := m.GetConnection(testCase.selection)
 
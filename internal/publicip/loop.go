@@ -6,6 +6,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/publicip/state"
@@ -47,15 +48,17 @@ type Loop struct {
 const defaultBackoffTime = 5 * time.Second
 
 func NewLoop(client *http.Client, logger Logger,
-	settings settings.PublicIP, puid, pgid int) *Loop {
+	settings settings.PublicIP, puid, pgid int,
+	publisher events.Publisher) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 	updateTicker := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
-	state := state.New(statusManager, settings, updateTicker)
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped,
+		"public ip", publisher)
+	state := state.New(statusManager, settings, updateTicker, publisher)
 
 	return &Loop{
 		statusManager: statusManager,
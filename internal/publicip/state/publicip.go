@@ -1,6 +1,7 @@
 package state
 
 import (
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/publicip/models"
 )
 
@@ -15,8 +16,14 @@ func (s *State) GetData() (data models.IPInfoData) {
 	return s.ipData.Copy()
 }
 
+// SetData sets the public IP data and publishes a public IP
+// event if the public IP address changed.
 func (s *State) SetData(data models.IPInfoData) {
 	s.ipDataMu.Lock()
 	defer s.ipDataMu.Unlock()
+	ipChanged := !s.ipData.IP.Equal(data.IP)
 	s.ipData = data.Copy()
+	if ipChanged {
+		s.publisher.Publish("public ip", events.TypePublicIP, data.Copy())
+	}
 }
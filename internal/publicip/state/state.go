@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/publicip/models"
 )
@@ -17,11 +18,12 @@ type Manager interface {
 
 func New(statusApplier loopstate.Applier,
 	settings settings.PublicIP,
-	updateTicker chan<- struct{}) *State {
+	updateTicker chan<- struct{}, publisher events.Publisher) *State {
 	return &State{
 		statusApplier: statusApplier,
 		settings:      settings,
 		updateTicker:  updateTicker,
+		publisher:     publisher,
 	}
 }
 
@@ -35,4 +37,6 @@ type State struct {
 	ipDataMu sync.RWMutex
 
 	updateTicker chan<- struct{}
+
+	publisher events.Publisher
 }
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+type role uint8
+
+const (
+	roleNone role = iota
+	roleReadOnly
+	roleAdmin
+)
+
+func (r role) String() string {
+	switch r {
+	case roleReadOnly:
+		return "read only"
+	case roleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// withAuthMiddleware returns the child handler wrapped with an
+// authentication and authorization check if authentication is
+// enabled in the settings, otherwise it returns the child handler.
+func withAuthMiddleware(childHandler http.Handler,
+	settings settings.ControlServerAuth) http.Handler {
+	if !settings.Enabled() {
+		return childHandler
+	}
+
+	return &authMiddleware{
+		childHandler: childHandler,
+		admin:        newCredentials(settings.Admin),
+		readOnly:     newCredentials(settings.ReadOnly),
+	}
+}
+
+type authMiddleware struct {
+	childHandler http.Handler
+	admin        credentials
+	readOnly     credentials
+}
+
+func (m *authMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isDashboardPath(r.URL.Path) {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	role := m.authenticate(r)
+	switch role {
+	case roleNone:
+		w.Header().Set("WWW-Authenticate", `Basic realm="gluetun"`)
+		writeJSONError(w, http.StatusUnauthorized, "authentication is required")
+		return
+	case roleReadOnly:
+		if !isReadOnlyRequest(r) {
+			writeJSONError(w, http.StatusForbidden,
+				"role "+role.String()+" is not allowed to "+r.Method+" "+r.RequestURI)
+			return
+		}
+	}
+	m.childHandler.ServeHTTP(w, r)
+}
+
+// authenticate returns the role matching the credentials of
+// the request, or roleNone if no credentials match.
+// The admin role is checked first.
+func (m *authMiddleware) authenticate(r *http.Request) role {
+	apiKey := r.Header.Get("X-API-Key")
+	user, password, basicAuthOk := r.BasicAuth()
+
+	switch {
+	case m.admin.matchAPIKey(apiKey),
+		basicAuthOk && m.admin.matchBasicAuth(user, password):
+		return roleAdmin
+	case m.readOnly.matchAPIKey(apiKey),
+		basicAuthOk && m.readOnly.matchBasicAuth(user, password):
+		return roleReadOnly
+	default:
+		return roleNone
+	}
+}
+
+// isReadOnlyRequest returns true if the request does not
+// change any state, which is the case for GET and HEAD requests,
+// except for the unversioned API restart routes.
+func isReadOnlyRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	switch strings.TrimSuffix(r.RequestURI, "/") {
+	case "/openvpn/actions/restart",
+		"/unbound/actions/restart",
+		"/updater/restart":
+		return false
+	default:
+		return true
+	}
+}
+
+type credentials struct {
+	apiKey   []byte
+	user     []byte
+	password []byte
+}
+
+func newCredentials(settings settings.ControlServerRole) credentials {
+	return credentials{
+		apiKey:   []byte(*settings.APIKey),
+		user:     []byte(*settings.User),
+		password: []byte(*settings.Password),
+	}
+}
+
+func (c credentials) matchAPIKey(apiKey string) bool {
+	if len(c.apiKey) == 0 || apiKey == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare(c.apiKey, []byte(apiKey)) == 1
+}
+
+func (c credentials) matchBasicAuth(user, password string) bool {
+	if len(c.user) == 0 {
+		return false
+	}
+	userMatch := subtle.ConstantTimeCompare(c.user, []byte(user))
+	passwordMatch := subtle.ConstantTimeCompare(c.password, []byte(password))
+	return userMatch&passwordMatch == 1
+}
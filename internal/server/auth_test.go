@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authMiddleware(t *testing.T) {
+	t.Parallel()
+
+	stringPtr := func(s string) *string { return &s }
+	authSettings := settings.ControlServerAuth{
+		Admin: settings.ControlServerRole{
+			APIKey:   stringPtr("admin-key"),
+			User:     stringPtr("admin"),
+			Password: stringPtr("admin-password"),
+		},
+		ReadOnly: settings.ControlServerRole{
+			APIKey:   stringPtr("readonly-key"),
+			User:     stringPtr(""),
+			Password: stringPtr(""),
+		},
+	}
+
+	childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := withAuthMiddleware(childHandler, authSettings)
+
+	testCases := map[string]struct {
+		method     string
+		uri        string
+		apiKey     string
+		user       string
+		password   string
+		statusCode int
+	}{
+		"no credentials": {
+			method:     http.MethodGet,
+			uri:        "/v1/openvpn/status",
+			statusCode: http.StatusUnauthorized,
+		},
+		"wrong API key": {
+			method:     http.MethodGet,
+			uri:        "/v1/openvpn/status",
+			apiKey:     "wrong",
+			statusCode: http.StatusUnauthorized,
+		},
+		"wrong password": {
+			method:     http.MethodGet,
+			uri:        "/v1/openvpn/status",
+			user:       "admin",
+			password:   "wrong",
+			statusCode: http.StatusUnauthorized,
+		},
+		"read only GET": {
+			method:     http.MethodGet,
+			uri:        "/v1/openvpn/status",
+			apiKey:     "readonly-key",
+			statusCode: http.StatusOK,
+		},
+		"read only PUT": {
+			method:     http.MethodPut,
+			uri:        "/v1/openvpn/status",
+			apiKey:     "readonly-key",
+			statusCode: http.StatusForbidden,
+		},
+		"read only unversioned restart": {
+			method:     http.MethodGet,
+			uri:        "/openvpn/actions/restart",
+			apiKey:     "readonly-key",
+			statusCode: http.StatusForbidden,
+		},
+		"admin API key PUT": {
+			method:     http.MethodPut,
+			uri:        "/v1/openvpn/status",
+			apiKey:     "admin-key",
+			statusCode: http.StatusOK,
+		},
+		"admin basic auth PUT": {
+			method:     http.MethodPut,
+			uri:        "/v1/openvpn/status",
+			user:       "admin",
+			password:   "admin-password",
+			statusCode: http.StatusOK,
+		},
+		"dashboard without credentials": {
+			method:     http.MethodGet,
+			uri:        "/dashboard/app.js",
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(testCase.method, testCase.uri, nil)
+			if testCase.apiKey != "" {
+				request.Header.Set("X-API-Key", testCase.apiKey)
+			}
+			if testCase.user != "" {
+				request.SetBasicAuth(testCase.user, testCase.password)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			if testCase.statusCode != http.StatusOK {
+				assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				assert.Contains(t, recorder.Body.String(), `"error":`)
+			}
+		})
+	}
+}
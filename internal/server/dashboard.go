@@ -0,0 +1,30 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:embed dashboard
+var dashboardFS embed.FS
+
+// newDashboardHandler returns a handler serving the embedded
+// dashboard web user interface files under /dashboard.
+func newDashboardHandler() http.Handler {
+	assets, err := fs.Sub(dashboardFS, "dashboard")
+	if err != nil {
+		panic(err) // the embedded directory always exists
+	}
+	fileServer := http.FileServer(http.FS(assets))
+	return http.StripPrefix("/dashboard", fileServer)
+}
+
+// isDashboardPath returns true if the path is one of the dashboard
+// static files. These files contain no data and are served without
+// authentication, so the dashboard can ask for an API key and send it
+// with its own requests to the API.
+func isDashboardPath(path string) bool {
+	return path == "/dashboard" || strings.HasPrefix(path, "/dashboard/")
+}
@@ -0,0 +1,123 @@
+"use strict";
+
+const refreshPeriodMs = 5000;
+const apiKeyStorageKey = "gluetun-api-key";
+
+// headers returns the request headers, with the API key stored for
+// this browser session if any. Basic authentication credentials
+// are instead handled by the browser itself.
+function headers(extra) {
+  const result = { Accept: "application/json", ...extra };
+  const apiKey = sessionStorage.getItem(apiKeyStorageKey);
+  if (apiKey) {
+    result["X-API-Key"] = apiKey;
+  }
+  return result;
+}
+
+async function getJSON(url) {
+  const response = await fetch(url, { headers: headers() });
+  if (response.status === 401) {
+    throw new Error(url + ": authentication is required, set a valid API key");
+  } else if (!response.ok) {
+    throw new Error(url + ": " + response.status + " " + response.statusText);
+  }
+  return response.json();
+}
+
+function setText(id, value) {
+  const element = document.getElementById(id);
+  element.textContent = value === undefined || value === null || value === "" ? "-" : String(value);
+  return element;
+}
+
+function setStatus(id, status) {
+  const element = setText(id, status);
+  element.className = status === "running" ? "good" : status === "crashed" ? "bad" : "";
+}
+
+async function refreshVPN() {
+  const connection = await getJSON("/v1/vpn/connection");
+  setStatus("vpn-status", connection.status);
+  setText("vpn-type", connection.type);
+  setText("vpn-provider", connection.provider);
+  setText("vpn-server", connection.server_name);
+  setText("vpn-endpoint", connection.endpoint);
+  setText("vpn-interface", connection.interface);
+  const portForwarded = await getJSON("/v1/vpn/portforwarded");
+  setText("vpn-port", portForwarded.port === 0 ? "" : portForwarded.port);
+}
+
+async function refreshPublicIP() {
+  const data = await getJSON("/v1/publicip/ip");
+  setText("ip-address", data.public_ip);
+  setText("ip-country", data.country);
+  setText("ip-region", data.region);
+  setText("ip-city", data.city);
+  setText("ip-org", data.org);
+}
+
+async function refreshDNS() {
+  const data = await getJSON("/v1/dns/status");
+  setStatus("dns-status", data.status);
+}
+
+async function refreshHealth() {
+  const data = await getJSON("/v1/health");
+  const element = setText("health-state", data.healthy ? "healthy" : "unhealthy");
+  element.className = data.healthy ? "good" : "bad";
+  setText("health-error", data.error);
+}
+
+async function refreshVersion() {
+  const data = await getJSON("/v1/version");
+  setText("version", data.version);
+}
+
+async function refresh() {
+  const results = await Promise.allSettled([
+    refreshVPN(), refreshPublicIP(), refreshDNS(), refreshHealth(),
+  ]);
+  const errors = results.filter((result) => result.status === "rejected");
+  setText("message", errors.map((result) => result.reason.message).join("; "));
+}
+
+async function putStatus(button) {
+  const buttons = document.querySelectorAll("button");
+  buttons.forEach((b) => { b.disabled = true; });
+  try {
+    const response = await fetch(button.dataset.url, {
+      method: "PUT",
+      headers: headers({ "Content-Type": "application/json" }),
+      body: JSON.stringify({ status: button.dataset.status }),
+    });
+    const text = await response.text();
+    setText("message", response.ok ? JSON.parse(text).outcome : text);
+  } catch (error) {
+    setText("message", error.message);
+  } finally {
+    buttons.forEach((b) => { b.disabled = false; });
+    refresh();
+  }
+}
+
+document.getElementById("api-key-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const input = document.getElementById("api-key");
+  if (input.value === "") {
+    sessionStorage.removeItem(apiKeyStorageKey);
+  } else {
+    sessionStorage.setItem(apiKeyStorageKey, input.value);
+  }
+  input.value = "";
+  refreshVersion().catch(() => {});
+  refresh();
+});
+
+document.querySelectorAll("button[data-url]").forEach((button) => {
+  button.addEventListener("click", () => putStatus(button));
+});
+
+refreshVersion().catch(() => {});
+refresh();
+setInterval(refresh, refreshPeriodMs);
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dashboardHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := newDashboardHandler()
+
+	testCases := map[string]struct {
+		uri         string
+		contentType string
+	}{
+		"index": {
+			uri:         "/dashboard/",
+			contentType: "text/html; charset=utf-8",
+		},
+		"index without trailing slash": {
+			uri:         "/dashboard",
+			contentType: "text/html; charset=utf-8",
+		},
+		"script": {
+			uri:         "/dashboard/app.js",
+			contentType: "text/javascript; charset=utf-8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, testCase.uri, nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, testCase.contentType, recorder.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSONError writes the status code and the message
+// wrapped in an errorWrapper JSON object to the response writer.
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(errorWrapper{Error: message})
+}
+
+// writeJSONFieldError is like writeJSONError but also sets
+// the settings field at fault in the errorWrapper JSON object.
+func writeJSONFieldError(w http.ResponseWriter, statusCode int,
+	field, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(errorWrapper{Error: message, Field: field})
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/gluetun/internal/subnet"
+)
+
+type firewallConfigurator interface {
+	firewall.PortAllower
+	firewall.OutboundSubnetsSetter
+	firewall.StateGetter
+}
+
+func newFirewallHandler(ctx context.Context, firewall firewallConfigurator,
+	routing routing.OutboundRoutesSetter, warner warner) http.Handler {
+	return &firewallHandler{
+		ctx:      ctx,
+		firewall: firewall,
+		routing:  routing,
+		warner:   warner,
+	}
+}
+
+type firewallHandler struct {
+	ctx      context.Context //nolint:containedctx
+	firewall firewallConfigurator
+	routing  routing.OutboundRoutesSetter
+	warner   warner
+	// outboundSubnetsMutex prevents concurrent read-modify-write
+	// changes of the outbound subnets.
+	outboundSubnetsMutex sync.Mutex
+}
+
+func (h *firewallHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/firewall")
+	switch r.RequestURI {
+	case "/state":
+		switch r.Method {
+		case http.MethodGet:
+			h.getState(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/inputports":
+		switch r.Method {
+		case http.MethodPost:
+			h.addInputPort(w, r)
+		case http.MethodDelete:
+			h.removeInputPort(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/outboundsubnets":
+		switch r.Method {
+		case http.MethodPost:
+			h.changeOutboundSubnet(w, r, false)
+		case http.MethodDelete:
+			h.changeOutboundSubnet(w, r, true)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *firewallHandler) getState(w http.ResponseWriter) {
+	state := h.firewall.GetState()
+
+	data := firewallStateWrapper{
+		Enabled:         state.Enabled,
+		VPNInterface:    state.VPNInterface,
+		OutboundSubnets: make([]string, len(state.OutboundSubnets)),
+		InputPorts:      make([]inputPortWrapper, 0, len(state.AllowedInputPorts)),
+	}
+	for i, outboundSubnet := range state.OutboundSubnets {
+		data.OutboundSubnets[i] = outboundSubnet.String()
+	}
+	for port, interfaces := range state.AllowedInputPorts {
+		data.InputPorts = append(data.InputPorts, inputPortWrapper{
+			Port:       port,
+			Interfaces: interfaces,
+		})
+	}
+	sort.Slice(data.InputPorts, func(i, j int) bool {
+		return data.InputPorts[i].Port < data.InputPorts[j].Port
+	})
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// addInputPort allows the input port given in the JSON body on the
+// network interface given, or on all the default route network
+// interfaces if no interface is given.
+func (h *firewallHandler) addInputPort(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var data inputPortChangeWrapper
+	if err := decoder.Decode(&data); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "cannot decode input port: "+err.Error())
+		return
+	} else if data.Port == 0 {
+		writeJSONError(w, http.StatusBadRequest, "port cannot be 0")
+		return
+	}
+
+	interfaces := []string{data.Interface}
+	if data.Interface == "" {
+		interfaces = h.firewall.GetState().DefaultInterfaces
+	}
+
+	for _, netInterface := range interfaces {
+		err := h.firewall.SetAllowedPort(h.ctx, data.Port, netInterface)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	h.writeOutcome(w, fmt.Sprintf("input port %d allowed", data.Port))
+}
+
+func (h *firewallHandler) removeInputPort(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var data inputPortChangeWrapper
+	if err := decoder.Decode(&data); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "cannot decode input port: "+err.Error())
+		return
+	} else if data.Interface != "" {
+		writeJSONError(w, http.StatusBadRequest,
+			"input port is removed from all interfaces and interface must not be set")
+		return
+	}
+
+	err := h.firewall.RemoveAllowedPort(h.ctx, data.Port)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeOutcome(w, fmt.Sprintf("input port %d removed", data.Port))
+}
+
+// changeOutboundSubnet adds or removes the outbound subnet given
+// in the JSON body, both in the firewall and in the routing.
+func (h *firewallHandler) changeOutboundSubnet(w http.ResponseWriter,
+	r *http.Request, remove bool) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var data outboundSubnetWrapper
+	if err := decoder.Decode(&data); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "cannot decode outbound subnet: "+err.Error())
+		return
+	}
+	_, ipNet, err := net.ParseCIDR(data.Subnet)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.outboundSubnetsMutex.Lock()
+	defer h.outboundSubnetsMutex.Unlock()
+
+	outboundSubnets := h.firewall.GetState().OutboundSubnets
+	var outcome string
+	if remove {
+		outboundSubnets = subnet.RemoveSubnetFromSubnets(outboundSubnets, *ipNet)
+		outcome = "outbound subnet " + ipNet.String() + " removed"
+	} else {
+		if subnet.SubnetInSubnets(*ipNet, outboundSubnets) {
+			writeJSONError(w, http.StatusConflict,
+				"outbound subnet "+ipNet.String()+" already exists")
+			return
+		}
+		outboundSubnets = append(outboundSubnets, *ipNet)
+		outcome = "outbound subnet " + ipNet.String() + " added"
+	}
+
+	err = h.firewall.SetOutboundSubnets(h.ctx, outboundSubnets)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError,
+			"cannot set firewall outbound subnets: "+err.Error())
+		return
+	}
+
+	err = h.routing.SetOutboundRoutes(outboundSubnets)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError,
+			"cannot set outbound routes: "+err.Error())
+		return
+	}
+
+	h.writeOutcome(w, outcome)
+}
+
+func (h *firewallHandler) writeOutcome(w http.ResponseWriter, outcome string) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
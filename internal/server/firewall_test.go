@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFirewall struct {
+	state firewall.State
+}
+
+func (t *testFirewall) SetAllowedPort(_ context.Context, port uint16, intf string) error {
+	t.state.AllowedInputPorts[port] = append(t.state.AllowedInputPorts[port], intf)
+	return nil
+}
+
+func (t *testFirewall) RemoveAllowedPort(_ context.Context, port uint16) error {
+	delete(t.state.AllowedInputPorts, port)
+	return nil
+}
+
+func (t *testFirewall) SetOutboundSubnets(_ context.Context, subnets []net.IPNet) error {
+	t.state.OutboundSubnets = subnets
+	return nil
+}
+
+func (t *testFirewall) GetState() firewall.State {
+	return t.state
+}
+
+type testRouting struct {
+	outboundSubnets []net.IPNet
+}
+
+func (t *testRouting) SetOutboundRoutes(outboundSubnets []net.IPNet) error {
+	t.outboundSubnets = outboundSubnets
+	return nil
+}
+
+func Test_firewallHandler(t *testing.T) {
+	t.Parallel()
+
+	firewallConf := &testFirewall{
+		state: firewall.State{
+			Enabled:           true,
+			DefaultInterfaces: []string{"eth0"},
+			AllowedInputPorts: map[uint16][]string{},
+		},
+	}
+	routingConf := &testRouting{}
+	handler := newFirewallHandler(context.Background(), firewallConf, routingConf, nil)
+
+	serve := func(method, uri, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, uri, strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := serve(http.MethodPost, "/firewall/inputports", `{"port":8000}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serve(http.MethodPost, "/firewall/inputports", `{"port":9000,"interface":"tun0"}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serve(http.MethodPost, "/firewall/outboundsubnets", `{"subnet":"192.168.1.0/24"}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serve(http.MethodPost, "/firewall/outboundsubnets", `{"subnet":"10.0.0.0/8"}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serve(http.MethodPost, "/firewall/outboundsubnets", `{"subnet":"10.1.2.3/8"}`)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, `{"error":"outbound subnet 10.0.0.0/8 already exists"}`+"\n",
+		recorder.Body.String())
+	recorder = serve(http.MethodDelete, "/firewall/outboundsubnets", `{"subnet":"192.168.1.0/24"}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serve(http.MethodDelete, "/firewall/inputports", `{"port":9000}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = serve(http.MethodPost, "/firewall/outboundsubnets", `{"subnet":"invalid"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = serve(http.MethodGet, "/firewall/state", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	const expectedBody = `{"enabled":true,"outbound_subnets":["10.0.0.0/8"],` +
+		`"input_ports":[{"port":8000,"interfaces":["eth0"]}]}` + "\n"
+	assert.Equal(t, expectedBody, recorder.Body.String())
+
+	require.Len(t, routingConf.outboundSubnets, 1)
+	assert.Equal(t, "10.0.0.0/8", routingConf.outboundSubnets[0].String())
+}
@@ -5,33 +5,61 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/logs"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/portforward"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/gluetun/internal/shadowsocks"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/gluetun/internal/vpn"
 )
 
 func newHandler(ctx context.Context, logger infoWarner, logging bool,
+	authSettings settings.ControlServerAuth,
 	buildInfo models.BuildInformation,
 	vpnLooper vpn.Looper,
 	pfGetter portforward.Getter,
 	unboundLooper dns.Looper,
 	updaterLooper updater.Looper,
 	publicIPLooper publicip.Looper,
+	httpProxyLooper httpproxy.Looper,
+	shadowsocksLooper shadowsocks.Looper,
+	firewallConf firewall.Configurator,
+	routingConf routing.OutboundRoutesSetter,
+	eventsSubscriber events.Subscriber,
+	logsBuffer *logs.Buffer,
+	healthGetter healthcheck.HealthGetter,
 ) http.Handler {
 	handler := &handler{}
 
-	openvpn := newOpenvpnHandler(ctx, vpnLooper, pfGetter, logger)
+	vpn := newVPNHandler(ctx, vpnLooper, pfGetter, logger)
+	openvpn := newOpenvpnHandler(vpn, logger)
 	dns := newDNSHandler(ctx, unboundLooper, logger)
 	updater := newUpdaterHandler(ctx, updaterLooper, logger)
 	publicip := newPublicIPHandler(publicIPLooper, logger)
+	events := newEventsHandler(ctx, eventsSubscriber, logger)
+	servers := newServersHandler(vpnLooper, logger)
+	httpProxy := newHTTPProxyHandler(ctx, httpProxyLooper, logger)
+	shadowsocks := newShadowsocksHandler(ctx, shadowsocksLooper, logger)
+	firewall := newFirewallHandler(ctx, firewallConf, routingConf, logger)
+	logs := newLogsHandler(ctx, logsBuffer, logger)
+	health := newHealthHandler(healthGetter, logger)
 
 	handler.v0 = newHandlerV0(ctx, logger, vpnLooper, unboundLooper, updaterLooper)
-	handler.v1 = newHandlerV1(logger, buildInfo, openvpn, dns, updater, publicip)
+	handler.v1 = newHandlerV1(logger, buildInfo, vpn, openvpn, dns, updater,
+		publicip, events, servers, httpProxy, shadowsocks,
+		firewall, logs, health)
+	handler.dashboard = newDashboardHandler()
 
-	handlerWithLog := withLogMiddleware(handler, logger, logging)
+	handlerWithAuth := withAuthMiddleware(handler, authSettings)
+	handlerWithLog := withLogMiddleware(handlerWithAuth, logger, logging)
 	handler.setLogEnabled = handlerWithLog.setEnabled
 
 	return handlerWithLog
@@ -40,10 +68,15 @@ func newHandler(ctx context.Context, logger infoWarner, logging bool,
 type handler struct {
 	v0            http.Handler
 	v1            http.Handler
+	dashboard     http.Handler
 	setLogEnabled func(enabled bool)
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isDashboardPath(r.URL.Path) {
+		h.dashboard.ServeHTTP(w, r)
+		return
+	}
 	r.RequestURI = strings.TrimSuffix(r.RequestURI, "/")
 	if !strings.HasPrefix(r.RequestURI, "/v1/") && r.RequestURI != "/v1" {
 		h.v0.ServeHTTP(w, r)
@@ -10,30 +10,51 @@ import (
 )
 
 func newHandlerV1(w warner, buildInfo models.BuildInformation,
-	openvpn, dns, updater, publicip http.Handler) http.Handler {
+	vpn, openvpn, dns, updater, publicip, events, servers, httpProxy, shadowsocks,
+	firewall, logs, health http.Handler) http.Handler {
 	return &handlerV1{
-		warner:    w,
-		buildInfo: buildInfo,
-		openvpn:   openvpn,
-		dns:       dns,
-		updater:   updater,
-		publicip:  publicip,
+		warner:      w,
+		buildInfo:   buildInfo,
+		vpn:         vpn,
+		openvpn:     openvpn,
+		dns:         dns,
+		updater:     updater,
+		publicip:    publicip,
+		events:      events,
+		servers:     servers,
+		httpProxy:   httpProxy,
+		shadowsocks: shadowsocks,
+		firewall:    firewall,
+		logs:        logs,
+		health:      health,
 	}
 }
 
 type handlerV1 struct {
-	warner    warner
-	buildInfo models.BuildInformation
-	openvpn   http.Handler
-	dns       http.Handler
-	updater   http.Handler
-	publicip  http.Handler
+	warner      warner
+	buildInfo   models.BuildInformation
+	vpn         http.Handler
+	openvpn     http.Handler
+	dns         http.Handler
+	updater     http.Handler
+	publicip    http.Handler
+	events      http.Handler
+	servers     http.Handler
+	httpProxy   http.Handler
+	shadowsocks http.Handler
+	firewall    http.Handler
+	logs        http.Handler
+	health      http.Handler
 }
 
 func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.RequestURI == "/version" && r.Method == http.MethodGet:
 		h.getVersion(w)
+	case r.RequestURI == "/openapi.json" && r.Method == http.MethodGet:
+		h.getOpenAPI(w)
+	case strings.HasPrefix(r.RequestURI, "/vpn"):
+		h.vpn.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/openvpn"):
 		h.openvpn.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/dns"):
@@ -42,6 +63,20 @@ func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.updater.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/publicip"):
 		h.publicip.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/events"):
+		h.events.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/servers"):
+		h.servers.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/httpproxy"):
+		h.httpProxy.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/shadowsocks"):
+		h.shadowsocks.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/firewall"):
+		h.firewall.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/logs"):
+		h.logs.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/health"):
+		h.health.ServeHTTP(w, r)
 	default:
 		errString := fmt.Sprintf("%s %s not found", r.Method, r.RequestURI)
 		http.Error(w, errString, http.StatusNotFound)
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/healthcheck"
+)
+
+func newHealthHandler(getter healthcheck.HealthGetter, w warner) http.Handler {
+	return &healthHandler{
+		getter: getter,
+		warner: w,
+	}
+}
+
+type healthHandler struct {
+	getter healthcheck.HealthGetter
+	warner warner
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/health")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHealth(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *healthHandler) getHealth(w http.ResponseWriter) {
+	data := healthWrapper{Healthy: true}
+	if err := h.getter.GetHealth(); err != nil {
+		data.Healthy = false
+		data.Error = err.Error()
+	}
+
+	results := h.getter.GetProbeResults()
+	data.Probes = make([]probeWrapper, len(results))
+	for i, result := range results {
+		data.Probes[i] = probeWrapper{
+			Type:      result.Target.Type,
+			Address:   result.Target.Address,
+			Healthy:   result.Err == nil,
+			LatencyMs: float64(result.Latency) / float64(time.Millisecond),
+		}
+		if result.Err != nil {
+			data.Probes[i].Error = result.Err.Error()
+		}
+	}
+
+	recovery := h.getter.GetRecovery()
+	data.Recovery = recoveryWrapper{
+		Attempts:       recovery.Attempts,
+		Restarts:       recovery.Restarts,
+		ServerSwitches: recovery.ServerSwitches,
+		TCPFallback:    recovery.TCPFallback,
+		LastStep:       recovery.LastStep,
+		LastOutcome:    recovery.LastOutcome,
+	}
+	if !recovery.LastTime.IsZero() {
+		data.Recovery.LastTime = &recovery.LastTime
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
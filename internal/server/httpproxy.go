@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+)
+
+func newHTTPProxyHandler(ctx context.Context, looper httpproxy.Looper,
+	warner warner) http.Handler {
+	return &httpProxyHandler{
+		ctx:    ctx,
+		looper: looper,
+		warner: warner,
+	}
+}
+
+type httpProxyHandler struct {
+	ctx    context.Context //nolint:containedctx
+	looper httpproxy.Looper
+	warner warner
+}
+
+func (h *httpProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/httpproxy")
+	switch r.RequestURI {
+	case "/status":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStatus(w)
+		case http.MethodPut:
+			h.setStatus(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/settings":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		case http.MethodPut:
+			h.setSettings(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *httpProxyHandler) getStatus(w http.ResponseWriter) {
+	status := h.looper.GetStatus()
+	encoder := json.NewEncoder(w)
+	data := statusWrapper{Status: string(status)}
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *httpProxyHandler) setStatus(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data statusWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := data.getStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outcome, err := h.looper.ApplyStatus(h.ctx, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *httpProxyHandler) getSettings(w http.ResponseWriter) {
+	settings := redactHTTPProxySettings(h.looper.GetSettings())
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(settings); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// setSettings overrides the current HTTP proxy settings with the
+// fields set in the JSON body, validates the resulting settings
+// and restarts the HTTP proxy if they changed. A password set to
+// the redacted placeholder is left unchanged.
+func (h *httpProxyHandler) setSettings(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var patch settings.HTTPProxy
+	if err := decoder.Decode(&patch); err != nil {
+		writeJSONError(w, http.StatusBadRequest,
+			"cannot decode HTTP proxy settings: "+err.Error())
+		return
+	}
+	patch.Password = unredactStringPtr(patch.Password)
+
+	httpProxySettings := h.looper.GetSettings()
+	err := httpProxySettings.OverrideWith(patch)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	outcome := h.looper.SetSettings(h.ctx, httpProxySettings)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+// redactHTTPProxySettings returns a copy of the HTTP proxy
+// settings with the password redacted.
+func redactHTTPProxySettings(httpProxySettings settings.HTTPProxy) settings.HTTPProxy {
+	httpProxySettings.Password = redactStringPtr(httpProxySettings.Password)
+	return httpProxySettings
+}
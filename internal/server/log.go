@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-func withLogMiddleware(childHandler http.Handler, logger infoer, enabled bool) *logMiddleware {
+func withLogMiddleware(childHandler http.Handler, logger infoWarner, enabled bool) *logMiddleware {
 	return &logMiddleware{
 		childHandler: childHandler,
 		logger:       logger,
@@ -18,25 +18,31 @@ func withLogMiddleware(childHandler http.Handler, logger infoer, enabled bool) *
 
 type logMiddleware struct {
 	childHandler http.Handler
-	logger       infoer
+	logger       infoWarner
 	timeNow      func() time.Time
 	enabled      bool
 	enabledMu    sync.RWMutex
 }
 
 func (m *logMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if !m.isEnabled() {
-		m.childHandler.ServeHTTP(w, r)
-		return
-	}
 	tStart := m.timeNow()
 	statefulWriter := &statefulResponseWriter{httpWriter: w}
 	m.childHandler.ServeHTTP(statefulWriter, r)
 	duration := m.timeNow().Sub(tStart)
-	m.logger.Info(strconv.Itoa(statefulWriter.statusCode) + " " +
+	line := strconv.Itoa(statefulWriter.statusCode) + " " +
 		r.Method + " " + r.RequestURI +
 		" wrote " + strconv.Itoa(statefulWriter.length) + "B to " +
-		r.RemoteAddr + " in " + duration.String())
+		r.RemoteAddr + " in " + duration.String()
+
+	switch statefulWriter.statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		// always log rejected requests, even if logging is disabled.
+		m.logger.Warn(line)
+	default:
+		if m.isEnabled() {
+			m.logger.Info(line)
+		}
+	}
 }
 
 func (m *logMiddleware) setEnabled(enabled bool) {
@@ -74,3 +80,16 @@ func (w *statefulResponseWriter) WriteHeader(statusCode int) {
 func (w *statefulResponseWriter) Header() http.Header {
 	return w.httpWriter.Header()
 }
+
+// Flush implements http.Flusher and is needed
+// to stream server-sent events.
+func (w *statefulResponseWriter) Flush() {
+	flusher, ok := w.httpWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	flusher.Flush()
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/logs"
+	"github.com/qdm12/log"
+)
+
+type logsBuffer interface {
+	Lines(filter logs.Filter) (lines []logs.Line)
+	Follow(filter logs.Filter) (lines []logs.Line,
+		newLines <-chan logs.Line, unsubscribe func())
+}
+
+func newLogsHandler(ctx context.Context, buffer logsBuffer,
+	w warner) http.Handler {
+	return &logsHandler{
+		ctx:    ctx,
+		buffer: buffer,
+		warner: w,
+		now:    time.Now,
+	}
+}
+
+type logsHandler struct {
+	ctx    context.Context //nolint:containedctx
+	buffer logsBuffer
+	warner warner
+	now    func() time.Time
+}
+
+func (h *logsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/logs")
+	path := r.RequestURI
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	switch path {
+	case "", "/":
+		switch r.Method {
+		case http.MethodGet:
+			h.getLogs(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *logsHandler) getLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := h.parseLogsFilter(query)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	follow := false
+	if value := query.Get("follow"); value != "" {
+		follow, err = strconv.ParseBool(value)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest,
+				fmt.Sprintf("query parameter follow: %s: %s", errQueryBoolNotValid, value))
+			return
+		}
+	}
+
+	if follow {
+		h.followLogs(w, r, filter)
+		return
+	}
+
+	data := logsWrapper{Lines: h.buffer.Lines(filter)}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseLogsFilter parses the component, level and since query
+// parameters. The level is the least severe level to return, and
+// since can be either an RFC3339 time or a duration such as 10m.
+func (h *logsHandler) parseLogsFilter(query url.Values) (
+	filter logs.Filter, err error) {
+	filter.Component = query.Get("component")
+
+	filter.MinLevel = log.LevelDebug
+	if value := query.Get("level"); value != "" {
+		filter.MinLevel, err = log.ParseLevel(value)
+		if err != nil {
+			return filter, fmt.Errorf("query parameter level: %w", err)
+		}
+	}
+
+	if value := query.Get("since"); value != "" {
+		filter.Since, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			duration, durationErr := time.ParseDuration(value)
+			if durationErr != nil {
+				return filter, fmt.Errorf("query parameter since: "+
+					"must be an RFC3339 time or a duration: %w", err)
+			}
+			filter.Since = h.now().Add(-duration)
+		}
+	}
+
+	return filter, nil
+}
+
+// followLogs streams the buffered log lines and the new log lines
+// matching the filter using server-sent events, until the client
+// disconnects or the server shuts down.
+func (h *logsHandler) followLogs(w http.ResponseWriter, r *http.Request,
+	filter logs.Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lines, newLines, unsubscribe := h.buffer.Follow(filter)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, line := range lines {
+		if err := h.writeLogEvent(w, line); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	const keepAlivePeriod = 15 * time.Second
+	ticker := time.NewTicker(keepAlivePeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, err := fmt.Fprint(w, ": keepalive\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case line, ok := <-newLines:
+			if !ok {
+				return
+			}
+			if err := h.writeLogEvent(w, line); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *logsHandler) writeLogEvent(w http.ResponseWriter, line logs.Line) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		h.warner.Warn("cannot encode log line: " + err.Error())
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "event: log\ndata: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI 3 specification of the v1 API.
+// It must be updated when a v1 route is added or changed.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func (h *handlerV1) getOpenAPI(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPISpec); err != nil {
+		h.warner.Warn(err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_openAPISpec_routes verifies the OpenAPI specification documents
+// exactly the v1 routes dispatched by the handlers, by parsing the
+// switch statements of the ServeHTTP methods of this package.
+func Test_openAPISpec_routes(t *testing.T) {
+	t.Parallel()
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	err := json.Unmarshal(openAPISpec, &spec)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	specRoutes := make(map[string]struct{})
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			specRoutes[strings.ToUpper(method)+" "+path] = struct{}{}
+		}
+	}
+
+	dispatchedRoutes := parseDispatchedRoutes(t)
+	// Routes with path parameters are not dispatched by a switch statement.
+	dispatchedRoutes["GET /v1/servers/{provider}"] = struct{}{}
+
+	assert.Equal(t, dispatchedRoutes, specRoutes)
+}
+
+func parseDispatchedRoutes(t *testing.T) (routes map[string]struct{}) {
+	t.Helper()
+
+	filePaths, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	routes = make(map[string]struct{})
+	fileSet := token.NewFileSet()
+	for _, filePath := range filePaths {
+		if strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fileSet, filePath, nil, 0)
+		require.NoError(t, err)
+
+		for _, declaration := range file.Decls {
+			function, ok := declaration.(*ast.FuncDecl)
+			if !ok || function.Name.Name != "ServeHTTP" || function.Recv == nil {
+				continue
+			}
+
+			receiverType := function.Recv.List[0].Type
+			if star, ok := receiverType.(*ast.StarExpr); ok {
+				receiverType = star.X
+			}
+			if ident, ok := receiverType.(*ast.Ident); ok && ident.Name == "handlerV0" {
+				// the unversioned API is not part of the specification
+				continue
+			}
+
+			prefix := "/v1" + findTrimmedPrefix(function.Body)
+			for _, route := range findSwitchRoutes(function.Body) {
+				routes[route.method+" "+prefix+route.path] = struct{}{}
+			}
+		}
+	}
+	return routes
+}
+
+type route struct {
+	method string
+	path   string
+}
+
+// findTrimmedPrefix returns the string literal prefix trimmed from
+// r.RequestURI using strings.TrimPrefix, or the empty string.
+func findTrimmedPrefix(body *ast.BlockStmt) (prefix string) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || !isSelector(call.Fun, "strings", "TrimPrefix") ||
+			len(call.Args) != 2 || !isSelector(call.Args[0], "r", "RequestURI") {
+			return true
+		}
+		if prefix, ok = stringLiteral(call.Args[1]); ok && prefix == "/v1" {
+			prefix = "" // top level handler dispatching to the v1 handler
+		}
+		return false
+	})
+	return prefix
+}
+
+// findSwitchRoutes returns the routes dispatched either by a switch
+// on the request URI containing a switch on the request method, or by
+// a tagless switch with cases such as
+// `r.RequestURI == "/path" && r.Method == http.MethodGet`.
+func findSwitchRoutes(body *ast.BlockStmt) (routes []route) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switchStmt, ok := node.(*ast.SwitchStmt)
+		if !ok {
+			return true
+		}
+
+		for _, statement := range switchStmt.Body.List {
+			caseClause := statement.(*ast.CaseClause)
+			if switchStmt.Tag == nil {
+				for _, expression := range caseClause.List {
+					if route, ok := parseRouteCondition(expression); ok {
+						routes = append(routes, route)
+					}
+				}
+				continue
+			}
+
+			if !isSelector(switchStmt.Tag, "r", "RequestURI") && !isIdent(switchStmt.Tag, "path") {
+				continue
+			}
+
+			methods := findSwitchMethods(caseClause.Body)
+			for _, expression := range caseClause.List {
+				path, ok := stringLiteral(expression)
+				if !ok {
+					continue
+				}
+				path = strings.TrimSuffix(path, "/")
+				for _, method := range methods {
+					routes = append(routes, route{method: method, path: path})
+				}
+			}
+		}
+		return false
+	})
+	return routes
+}
+
+func findSwitchMethods(statements []ast.Stmt) (methods []string) {
+	for _, statement := range statements {
+		switchStmt, ok := statement.(*ast.SwitchStmt)
+		if !ok || !isSelector(switchStmt.Tag, "r", "Method") {
+			continue
+		}
+		for _, caseStatement := range switchStmt.Body.List {
+			for _, expression := range caseStatement.(*ast.CaseClause).List {
+				if method, ok := httpMethod(expression); ok {
+					methods = append(methods, method)
+				}
+			}
+		}
+	}
+	return methods
+}
+
+func parseRouteCondition(expression ast.Expr) (r route, ok bool) {
+	and, ok := expression.(*ast.BinaryExpr)
+	if !ok || and.Op != token.LAND {
+		return r, false
+	}
+
+	for _, operand := range []ast.Expr{and.X, and.Y} {
+		equal, ok := operand.(*ast.BinaryExpr)
+		if !ok || equal.Op != token.EQL {
+			return r, false
+		}
+		switch {
+		case isSelector(equal.X, "r", "RequestURI"):
+			r.path, ok = stringLiteral(equal.Y)
+		case isSelector(equal.X, "r", "Method"):
+			r.method, ok = httpMethod(equal.Y)
+		}
+		if !ok {
+			return r, false
+		}
+	}
+	return r, r.path != "" && r.method != ""
+}
+
+func httpMethod(expression ast.Expr) (method string, ok bool) {
+	selector, ok := expression.(*ast.SelectorExpr)
+	if !ok || !isIdent(selector.X, "http") ||
+		!strings.HasPrefix(selector.Sel.Name, "Method") {
+		return "", false
+	}
+	return strings.ToUpper(strings.TrimPrefix(selector.Sel.Name, "Method")), true
+}
+
+func isSelector(expression ast.Expr, x, sel string) bool {
+	selector, ok := expression.(*ast.SelectorExpr)
+	return ok && isIdent(selector.X, x) && selector.Sel.Name == sel
+}
+
+func isIdent(expression ast.Expr, name string) bool {
+	ident, ok := expression.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func stringLiteral(expression ast.Expr) (value string, ok bool) {
+	literal, ok := expression.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
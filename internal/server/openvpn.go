@@ -1,29 +1,22 @@
 package server
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
-
-	"github.com/qdm12/gluetun/internal/portforward"
-	"github.com/qdm12/gluetun/internal/vpn"
 )
 
-func newOpenvpnHandler(ctx context.Context, looper vpn.Looper,
-	pfGetter portforward.Getter, w warner) http.Handler {
+// newOpenvpnHandler returns a handler for the /openvpn routes,
+// kept for compatibility with the VPN type agnostic /vpn routes.
+func newOpenvpnHandler(vpnHandler *vpnHandler, w warner) http.Handler {
 	return &openvpnHandler{
-		ctx:    ctx,
-		looper: looper,
-		pf:     pfGetter,
+		vpn:    vpnHandler,
 		warner: w,
 	}
 }
 
 type openvpnHandler struct {
-	ctx    context.Context //nolint:containedctx
-	looper vpn.Looper
-	pf     portforward.Getter
+	vpn    *vpnHandler
 	warner warner
 }
 
@@ -33,9 +26,9 @@ func (h *openvpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "/status":
 		switch r.Method {
 		case http.MethodGet:
-			h.getStatus(w)
+			h.vpn.getStatus(w)
 		case http.MethodPut:
-			h.setStatus(w, r)
+			h.vpn.setStatus(w, r)
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
@@ -49,7 +42,7 @@ func (h *openvpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "/portforwarded":
 		switch r.Method {
 		case http.MethodGet:
-			h.getPortForwarded(w)
+			h.vpn.getPortForwarded(w)
 		default:
 			http.Error(w, "", http.StatusNotFound)
 		}
@@ -58,47 +51,9 @@ func (h *openvpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *openvpnHandler) getStatus(w http.ResponseWriter) {
-	status := h.looper.GetStatus()
-	encoder := json.NewEncoder(w)
-	data := statusWrapper{Status: string(status)}
-	if err := encoder.Encode(data); err != nil {
-		h.warner.Warn(err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-}
-
-func (h *openvpnHandler) setStatus(w http.ResponseWriter, r *http.Request) {
-	decoder := json.NewDecoder(r.Body)
-	var data statusWrapper
-	if err := decoder.Decode(&data); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	status, err := data.getStatus()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	outcome, err := h.looper.ApplyStatus(h.ctx, status)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
-		h.warner.Warn(err.Error())
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return
-	}
-}
-
 func (h *openvpnHandler) getSettings(w http.ResponseWriter) {
-	vpnSettings := h.looper.GetSettings()
-	settings := vpnSettings.OpenVPN
-	settings.User = "redacted"
-	settings.Password = "redacted"
+	vpnSettings := h.vpn.looper.GetSettings()
+	settings := redactOpenVPNSettings(vpnSettings.OpenVPN)
 	encoder := json.NewEncoder(w)
 	if err := encoder.Encode(settings); err != nil {
 		h.warner.Warn(err.Error())
@@ -106,14 +61,3 @@ func (h *openvpnHandler) getSettings(w http.ResponseWriter) {
 		return
 	}
 }
-
-func (h *openvpnHandler) getPortForwarded(w http.ResponseWriter) {
-	port := h.pf.GetPortForwarded()
-	encoder := json.NewEncoder(w)
-	data := portWrapper{Port: port}
-	if err := encoder.Encode(data); err != nil {
-		h.warner.Warn(err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-}
@@ -5,26 +5,41 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/healthcheck"
+	"github.com/qdm12/gluetun/internal/httpproxy"
 	"github.com/qdm12/gluetun/internal/httpserver"
+	"github.com/qdm12/gluetun/internal/logs"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/portforward"
 	"github.com/qdm12/gluetun/internal/publicip"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/qdm12/gluetun/internal/shadowsocks"
 	"github.com/qdm12/gluetun/internal/updater"
 	"github.com/qdm12/gluetun/internal/vpn"
 )
 
-func New(ctx context.Context, address string, logEnabled bool, logger Logger,
+func New(ctx context.Context, settings settings.ControlServer, logger Logger,
 	buildInfo models.BuildInformation, openvpnLooper vpn.Looper,
 	pfGetter portforward.Getter, unboundLooper dns.Looper,
-	updaterLooper updater.Looper, publicIPLooper publicip.Looper) (server httpserver.Runner, err error) {
-	handler := newHandler(ctx, logger, logEnabled, buildInfo,
-		openvpnLooper, pfGetter, unboundLooper, updaterLooper, publicIPLooper)
+	updaterLooper updater.Looper, publicIPLooper publicip.Looper,
+	httpProxyLooper httpproxy.Looper, shadowsocksLooper shadowsocks.Looper,
+	firewallConf firewall.Configurator, routingConf routing.OutboundRoutesSetter,
+	eventsSubscriber events.Subscriber, logsBuffer *logs.Buffer,
+	healthGetter healthcheck.HealthGetter) (server httpserver.Runner, err error) {
+	handler := newHandler(ctx, logger, *settings.Log, settings.Auth, buildInfo,
+		openvpnLooper, pfGetter, unboundLooper, updaterLooper, publicIPLooper,
+		httpProxyLooper, shadowsocksLooper, firewallConf, routingConf,
+		eventsSubscriber, logsBuffer, healthGetter)
 
 	httpServerSettings := httpserver.Settings{
-		Address: address,
+		Address: *settings.Address,
 		Handler: handler,
 		Logger:  logger,
+		TLS:     settings.TLS,
 	}
 
 	server, err = httpserver.New(httpServerSettings)
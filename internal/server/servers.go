@@ -0,0 +1,312 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+)
+
+type serversGetter interface {
+	GetServers() (servers models.AllServers)
+}
+
+func newServersHandler(getter serversGetter, w warner) *serversHandler {
+	return &serversHandler{
+		getter: getter,
+		warner: w,
+	}
+}
+
+type serversHandler struct {
+	getter serversGetter
+	warner warner
+}
+
+func (h *serversHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/servers")
+	path := r.RequestURI
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimSuffix(path, "/")
+
+	if r.Method != http.MethodGet || !strings.HasPrefix(path, "/") ||
+		strings.Count(path, "/") != 1 {
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+
+	provider, err := url.PathUnescape(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.getServers(w, r, strings.ToLower(provider))
+}
+
+const (
+	defaultServersPerPage = 100
+	maxServersPerPage     = 1000
+)
+
+var (
+	errQueryBoolNotValid     = errors.New("boolean value is not valid")
+	errQueryNumberNotValid   = errors.New("number is not valid")
+	errQueryVPNNotValid      = errors.New("vpn type is not valid")
+	errQueryProtocolNotValid = errors.New("protocol is not valid")
+)
+
+func (h *serversHandler) getServers(w http.ResponseWriter, r *http.Request,
+	provider string) {
+	allServers := h.getter.GetServers()
+	servers, ok := allServers.GetServersByProvider(provider)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound,
+			"no servers data for provider "+provider)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := parseServersFilter(query)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, perPage, err := parsePagination(query)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filtered := make([]models.Server, 0, len(servers))
+	for _, server := range servers {
+		if !filter.excludes(server) {
+			filtered = append(filtered, server)
+		}
+	}
+
+	start := (page - 1) * perPage
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+	end := start + perPage
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+
+	data := serversWrapper{
+		Provider:     provider,
+		Page:         page,
+		PerPage:      perPage,
+		Total:        len(filtered),
+		Servers:      filtered[start:end],
+		FilterValues: newServersFilterValues(servers),
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// serversFilter contains the filters supported by the
+// servers route, which are the same as the server selection ones.
+type serversFilter struct {
+	vpn          string
+	protocol     string
+	countries    []string
+	regions      []string
+	cities       []string
+	isps         []string
+	names        []string
+	numbers      []uint16
+	hostnames    []string
+	ownedOnly    bool
+	freeOnly     bool
+	streamOnly   bool
+	multiHopOnly bool
+}
+
+func parseServersFilter(query url.Values) (filter serversFilter, err error) {
+	filter.countries = queryToStrings(query, "countries")
+	filter.regions = queryToStrings(query, "regions")
+	filter.cities = queryToStrings(query, "cities")
+	filter.isps = queryToStrings(query, "isps")
+	filter.names = queryToStrings(query, "names")
+	filter.hostnames = queryToStrings(query, "hostnames")
+
+	for _, s := range queryToStrings(query, "numbers") {
+		const base, bitSize = 10, 16
+		number, err := strconv.ParseUint(s, base, bitSize)
+		if err != nil {
+			return filter, fmt.Errorf("query parameter numbers: %w: %s",
+				errQueryNumberNotValid, s)
+		}
+		filter.numbers = append(filter.numbers, uint16(number))
+	}
+
+	boolKeyToField := map[string]*bool{
+		"owned_only":    &filter.ownedOnly,
+		"free_only":     &filter.freeOnly,
+		"stream_only":   &filter.streamOnly,
+		"multihop_only": &filter.multiHopOnly,
+	}
+	for key, field := range boolKeyToField {
+		value := query.Get(key)
+		if value == "" {
+			continue
+		}
+		*field, err = strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("query parameter %s: %w: %s",
+				key, errQueryBoolNotValid, value)
+		}
+	}
+
+	filter.vpn = strings.ToLower(query.Get("vpn"))
+	switch filter.vpn {
+	case "", constants.OpenVPN, constants.Wireguard:
+	default:
+		return filter, fmt.Errorf("query parameter vpn: %w: %s",
+			errQueryVPNNotValid, filter.vpn)
+	}
+
+	filter.protocol = strings.ToLower(query.Get("protocol"))
+	switch filter.protocol {
+	case "", constants.TCP, constants.UDP:
+	default:
+		return filter, fmt.Errorf("query parameter protocol: %w: %s",
+			errQueryProtocolNotValid, filter.protocol)
+	}
+
+	return filter, nil
+}
+
+// queryToStrings returns the values for the query key given,
+// where each value can also be a comma separated list.
+func queryToStrings(query url.Values, key string) (values []string) {
+	for _, value := range query[key] {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field != "" {
+				values = append(values, field)
+			}
+		}
+	}
+	return values
+}
+
+func parsePagination(query url.Values) (page, perPage int, err error) {
+	page, perPage = 1, defaultServersPerPage
+
+	if value := query.Get("page"); value != "" {
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("query parameter page: %w: %s",
+				errQueryNumberNotValid, value)
+		}
+	}
+
+	if value := query.Get("per_page"); value != "" {
+		perPage, err = strconv.Atoi(value)
+		if err != nil || perPage < 1 || perPage > maxServersPerPage {
+			return 0, 0, fmt.Errorf("query parameter per_page: %w: %s",
+				errQueryNumberNotValid, value)
+		}
+	}
+
+	return page, perPage, nil
+}
+
+func (f serversFilter) excludes(server models.Server) bool {
+	serverVPN := server.VPN
+	if serverVPN == "" {
+		serverVPN = constants.OpenVPN
+	}
+
+	// Servers data without any protocol information
+	// supports both TCP and UDP.
+	hasProtocolInfo := server.TCP || server.UDP
+
+	switch {
+	case
+		f.vpn != "" && serverVPN != f.vpn,
+		f.protocol == constants.TCP && hasProtocolInfo && !server.TCP,
+		f.protocol == constants.UDP && hasProtocolInfo && !server.UDP,
+		utils.FilterByPossibilities(server.Country, f.countries),
+		utils.FilterByPossibilities(server.Region, f.regions),
+		utils.FilterByPossibilities(server.City, f.cities),
+		utils.FilterByPossibilities(server.ISP, f.isps),
+		utils.FilterByPossibilities(server.ServerName, f.names),
+		utils.FilterByPossibilities(server.Hostname, f.hostnames),
+		len(f.numbers) > 0 && !containsNumber(f.numbers, server.Number),
+		f.ownedOnly && !server.Owned,
+		f.freeOnly && !server.Free,
+		f.streamOnly && !server.Stream,
+		f.multiHopOnly && !server.MultiHop:
+		return true
+	default:
+		return false
+	}
+}
+
+func containsNumber(numbers []uint16, number uint16) bool {
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+	return false
+}
+
+func newServersFilterValues(servers []models.Server) (values serversFilterValues) {
+	countries := make(map[string]struct{})
+	regions := make(map[string]struct{})
+	cities := make(map[string]struct{})
+	isps := make(map[string]struct{})
+	names := make(map[string]struct{})
+	hostnames := make(map[string]struct{})
+	for _, server := range servers {
+		addNonEmpty(countries, server.Country)
+		addNonEmpty(regions, server.Region)
+		addNonEmpty(cities, server.City)
+		addNonEmpty(isps, server.ISP)
+		addNonEmpty(names, server.ServerName)
+		addNonEmpty(hostnames, server.Hostname)
+	}
+
+	return serversFilterValues{
+		Countries: sortedKeys(countries),
+		Regions:   sortedKeys(regions),
+		Cities:    sortedKeys(cities),
+		ISPs:      sortedKeys(isps),
+		Names:     sortedKeys(names),
+		Hostnames: sortedKeys(hostnames),
+	}
+}
+
+func addNonEmpty(set map[string]struct{}, value string) {
+	if value != "" {
+		set[value] = struct{}{}
+	}
+}
+
+func sortedKeys(set map[string]struct{}) (keys []string) {
+	keys = make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServersGetter struct {
+	servers models.AllServers
+}
+
+func (t *testServersGetter) GetServers() models.AllServers {
+	return t.servers
+}
+
+func Test_serversHandler(t *testing.T) {
+	t.Parallel()
+
+	getter := &testServersGetter{
+		servers: models.AllServers{
+			Mullvad: models.Servers{
+				Servers: []models.Server{
+					{VPN: "openvpn", Country: "Sweden", City: "Stockholm", Hostname: "se1", Owned: true},
+					{VPN: "wireguard", Country: "Sweden", City: "Malmo", Hostname: "se2"},
+					{VPN: "openvpn", Country: "France", City: "Paris", Hostname: "fr1", Owned: true},
+				},
+			},
+			Pia: models.Servers{
+				Servers: []models.Server{
+					{Region: "Spain", ServerName: "madrid401", TCP: true},
+					{Region: "Spain", ServerName: "madrid402", UDP: true},
+				},
+			},
+		},
+	}
+	handler := newServersHandler(getter, nil)
+
+	testCases := map[string]struct {
+		uri          string
+		statusCode   int
+		total        int
+		hostnames    []string
+		names        []string
+		filterValues serversFilterValues
+	}{
+		"unknown provider": {
+			uri:        "/servers/unknown",
+			statusCode: http.StatusNotFound,
+		},
+		"invalid boolean": {
+			uri:        "/servers/mullvad?owned_only=maybe",
+			statusCode: http.StatusBadRequest,
+		},
+		"no filter": {
+			uri:        "/servers/mullvad",
+			statusCode: http.StatusOK,
+			total:      3,
+			hostnames:  []string{"se1", "se2", "fr1"},
+			filterValues: serversFilterValues{
+				Countries: []string{"France", "Sweden"},
+				Regions:   []string{},
+				Cities:    []string{"Malmo", "Paris", "Stockholm"},
+				ISPs:      []string{},
+				Names:     []string{},
+				Hostnames: []string{"fr1", "se1", "se2"},
+			},
+		},
+		"filters": {
+			uri:        "/servers/mullvad?countries=sweden,france&owned_only=true&vpn=openvpn",
+			statusCode: http.StatusOK,
+			total:      2,
+			hostnames:  []string{"se1", "fr1"},
+		},
+		"pagination": {
+			uri:        "/servers/mullvad?page=2&per_page=2",
+			statusCode: http.StatusOK,
+			total:      3,
+			hostnames:  []string{"fr1"},
+		},
+		"escaped provider and protocol": {
+			uri:        "/servers/private%20internet%20access?protocol=udp",
+			statusCode: http.StatusOK,
+			total:      1,
+			names:      []string{"madrid402"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, testCase.uri, nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, testCase.statusCode, recorder.Code)
+			if testCase.statusCode != http.StatusOK {
+				return
+			}
+
+			var data serversWrapper
+			err := json.NewDecoder(recorder.Body).Decode(&data)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.total, data.Total)
+			hostnames := make([]string, 0, len(data.Servers))
+			names := make([]string, 0, len(data.Servers))
+			for _, server := range data.Servers {
+				if server.Hostname != "" {
+					hostnames = append(hostnames, server.Hostname)
+				}
+				if server.ServerName != "" {
+					names = append(names, server.ServerName)
+				}
+			}
+			if testCase.hostnames != nil {
+				assert.Equal(t, testCase.hostnames, hostnames)
+			}
+			if testCase.names != nil {
+				assert.Equal(t, testCase.names, names)
+			}
+			if testCase.filterValues.Countries != nil {
+				assert.Equal(t, testCase.filterValues, data.FilterValues)
+			}
+		})
+	}
+}
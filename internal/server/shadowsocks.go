@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/shadowsocks"
+)
+
+func newShadowsocksHandler(ctx context.Context, looper shadowsocks.Looper,
+	warner warner) http.Handler {
+	return &shadowsocksHandler{
+		ctx:    ctx,
+		looper: looper,
+		warner: warner,
+	}
+}
+
+type shadowsocksHandler struct {
+	ctx    context.Context //nolint:containedctx
+	looper shadowsocks.Looper
+	warner warner
+}
+
+func (h *shadowsocksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/shadowsocks")
+	switch r.RequestURI {
+	case "/status":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStatus(w)
+		case http.MethodPut:
+			h.setStatus(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/settings":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		case http.MethodPut:
+			h.setSettings(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *shadowsocksHandler) getStatus(w http.ResponseWriter) {
+	status := h.looper.GetStatus()
+	encoder := json.NewEncoder(w)
+	data := statusWrapper{Status: string(status)}
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *shadowsocksHandler) setStatus(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data statusWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := data.getStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outcome, err := h.looper.SetStatus(h.ctx, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *shadowsocksHandler) getSettings(w http.ResponseWriter) {
+	settings := redactShadowsocksSettings(h.looper.GetSettings())
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(settings); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// setSettings overrides the current Shadowsocks settings with the
+// fields set in the JSON body, validates the resulting settings
+// and restarts the Shadowsocks server if they changed. Passwords
+// set to the redacted placeholder are left unchanged.
+func (h *shadowsocksHandler) setSettings(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var patch settings.Shadowsocks
+	if err := decoder.Decode(&patch); err != nil {
+		writeJSONError(w, http.StatusBadRequest,
+			"cannot decode Shadowsocks settings: "+err.Error())
+		return
+	}
+	patch.Password = unredactStringPtr(patch.Password)
+	patch.TCP.Password = unredactStringPtr(patch.TCP.Password)
+	patch.UDP.Password = unredactStringPtr(patch.UDP.Password)
+
+	shadowsocksSettings := h.looper.GetSettings()
+	err := shadowsocksSettings.OverrideWith(patch)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	outcome := h.looper.SetSettings(h.ctx, shadowsocksSettings)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+// redactShadowsocksSettings returns a copy of the Shadowsocks
+// settings with the passwords redacted.
+func redactShadowsocksSettings(shadowsocksSettings settings.Shadowsocks) settings.Shadowsocks {
+	shadowsocksSettings.Password = redactStringPtr(shadowsocksSettings.Password)
+	shadowsocksSettings.TCP.Password = redactStringPtr(shadowsocksSettings.TCP.Password)
+	shadowsocksSettings.UDP.Password = redactStringPtr(shadowsocksSettings.UDP.Password)
+	return shadowsocksSettings
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/shadowsocks"
+	"github.com/qdm12/ss-server/pkg/tcpudp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_redactShadowsocksSettings(t *testing.T) {
+	t.Parallel()
+
+	stringPtr := func(s string) *string { return &s }
+	original := settings.Shadowsocks{
+		Settings: tcpudp.Settings{
+			Address:  ":8388",
+			Password: stringPtr("password"),
+		},
+	}
+
+	redactedSettings := redactShadowsocksSettings(original)
+
+	assert.Equal(t, ":8388", redactedSettings.Address)
+	assert.Equal(t, "redacted", *redactedSettings.Password)
+	assert.Nil(t, redactedSettings.TCP.Password)
+	assert.Nil(t, redactedSettings.UDP.Password)
+
+	// Original settings pointed values must be left untouched
+	assert.Equal(t, "password", *original.Password)
+}
+
+type testShadowsocksLooper struct {
+	shadowsocks.Looper
+	settings settings.Shadowsocks
+}
+
+func (t *testShadowsocksLooper) GetSettings() settings.Shadowsocks { return t.settings }
+
+func (t *testShadowsocksLooper) SetSettings(_ context.Context,
+	shadowsocksSettings settings.Shadowsocks) string {
+	t.settings = shadowsocksSettings
+	return "settings updated"
+}
+
+func Test_shadowsocksHandler_getModifyPut(t *testing.T) {
+	t.Parallel()
+
+	var allSettings settings.Settings
+	allSettings.SetDefaults()
+	shadowsocksSettings := allSettings.Shadowsocks
+	password := "password"
+	shadowsocksSettings.Password = &password
+	looper := &testShadowsocksLooper{settings: shadowsocksSettings}
+	handler := newShadowsocksHandler(context.Background(), looper, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/shadowsocks/settings", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+	require.NoError(t, err)
+	require.Equal(t, "redacted", body["Password"])
+	body["Address"] = ":8389"
+	modifiedBody, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	request = httptest.NewRequest(http.MethodPut, "/shadowsocks/settings",
+		bytes.NewReader(modifiedBody))
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, ":8389", looper.settings.Address)
+	assert.Equal(t, "password", *looper.settings.Password)
+}
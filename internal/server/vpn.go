@@ -0,0 +1,409 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/portforward"
+	"github.com/qdm12/gluetun/internal/vpn"
+)
+
+func newVPNHandler(ctx context.Context, looper vpn.Looper,
+	pfGetter portforward.Getter, w warner) *vpnHandler {
+	return &vpnHandler{
+		ctx:    ctx,
+		looper: looper,
+		pf:     pfGetter,
+		warner: w,
+	}
+}
+
+type vpnHandler struct {
+	ctx    context.Context //nolint:containedctx
+	looper vpn.Looper
+	pf     portforward.Getter
+	warner warner
+}
+
+func (h *vpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/vpn")
+	switch r.RequestURI {
+	case "/status":
+		switch r.Method {
+		case http.MethodGet:
+			h.getStatus(w)
+		case http.MethodPut:
+			h.setStatus(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/settings":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		case http.MethodPut:
+			h.setSettings(w, r)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/connection":
+		switch r.Method {
+		case http.MethodGet:
+			h.getConnection(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/portforwarded":
+		switch r.Method {
+		case http.MethodGet:
+			h.getPortForwarded(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/rotate":
+		switch r.Method {
+		case http.MethodPost:
+			h.rotate(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/profile":
+		switch r.Method {
+		case http.MethodGet:
+			h.getProfile(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	case "/blocklist":
+		switch r.Method {
+		case http.MethodGet:
+			h.getBlocklist(w)
+		case http.MethodDelete:
+			h.clearBlocklist(w)
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	default:
+		http.Error(w, "", http.StatusNotFound)
+	}
+}
+
+func (h *vpnHandler) getStatus(w http.ResponseWriter) {
+	status := h.looper.GetStatus()
+	encoder := json.NewEncoder(w)
+	data := statusWrapper{Status: string(status)}
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) setStatus(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data statusWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status, err := data.getStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	outcome, err := h.looper.ApplyStatus(h.ctx, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getSettings(w http.ResponseWriter) {
+	settings := redactVPNSettings(h.looper.GetSettings())
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(settings); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// setSettings overrides the current server selection settings with
+// the fields set in the server selection JSON body, validates the
+// resulting settings and reconnects the VPN if they changed.
+// Fields absent or null are left unchanged, and a filter list field
+// set to an empty array such as "Countries": [] clears the filter.
+func (h *vpnHandler) setSettings(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var serverSelection settings.ServerSelection
+	if err := decoder.Decode(&serverSelection); err != nil {
+		writeJSONError(w, http.StatusBadRequest,
+			"cannot decode server selection: "+err.Error())
+		return
+	}
+	// The server selection VPN type is tied to the VPN type
+	// and cannot be changed on its own.
+	serverSelection.VPN = ""
+	lowercaseFilters(serverSelection)
+
+	vpnSettings := h.looper.GetSettings()
+	patch := settings.VPN{
+		Provider: settings.Provider{
+			ServerSelection: serverSelection,
+		},
+	}
+	err := vpnSettings.OverrideWith(patch, h.looper.GetServers())
+	if err != nil {
+		writeJSONFieldError(w, http.StatusBadRequest,
+			serverSelectionField(err), err.Error())
+		return
+	}
+
+	outcome := h.looper.SetSettings(h.ctx, vpnSettings)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+// lowercaseFilters lowercases in place the values of the server
+// selection filter lists, which are expected to be lowercased for
+// validation and filtering, as done by the settings sources.
+func lowercaseFilters(serverSelection settings.ServerSelection) {
+	filters := [][]string{
+		serverSelection.Countries,
+		serverSelection.Regions,
+		serverSelection.Cities,
+		serverSelection.ISPs,
+		serverSelection.Names,
+		serverSelection.Hostnames,
+	}
+	for _, values := range filters {
+		for i := range values {
+			values[i] = strings.ToLower(values[i])
+		}
+	}
+}
+
+// serverSelectionFields maps server selection validation
+// errors to the server selection JSON field at fault.
+var serverSelectionFields = []struct { //nolint:gochecknoglobals
+	err   error
+	field string
+}{
+	{err: settings.ErrCountryNotValid, field: "Countries"},
+	{err: settings.ErrRegionNotValid, field: "Regions"},
+	{err: settings.ErrCityNotValid, field: "Cities"},
+	{err: settings.ErrISPNotValid, field: "ISPs"},
+	{err: settings.ErrNameNotValid, field: "Names"},
+	{err: settings.ErrHostnameNotValid, field: "Hostnames"},
+	{err: settings.ErrOwnedOnlyNotSupported, field: "OwnedOnly"},
+	{err: settings.ErrFreeOnlyNotSupported, field: "FreeOnly"},
+	{err: settings.ErrStreamOnlyNotSupported, field: "StreamOnly"},
+	{err: settings.ErrMultiHopOnlyNotSupported, field: "MultiHopOnly"},
+	{err: settings.ErrPortForwardOnlyNotSupported, field: "PortForwardOnly"},
+	{err: settings.ErrSelectionStrategyNotValid, field: "Strategy"},
+	{err: settings.ErrOpenVPNTCPNotSupported, field: "OpenVPN.TCP"},
+	{err: settings.ErrOpenVPNCustomPortNotAllowed, field: "OpenVPN.CustomPort"},
+	{err: settings.ErrOpenVPNEncryptionPresetNotValid, field: "OpenVPN.PIAEncPreset"},
+	{err: settings.ErrWireguardEndpointIPNotSet, field: "Wireguard.EndpointIP"},
+	{err: settings.ErrWireguardEndpointPortNotSet, field: "Wireguard.EndpointPort"},
+	{err: settings.ErrWireguardEndpointPortNotAllowed, field: "Wireguard.EndpointPort"},
+	{err: settings.ErrWireguardPublicKeyNotSet, field: "Wireguard.PublicKey"},
+	{err: settings.ErrWireguardPublicKeyNotValid, field: "Wireguard.PublicKey"},
+}
+
+// serverSelectionField returns the server selection JSON field
+// at fault for the validation error given, or the empty string
+// if the error is not specific to a server selection field.
+func serverSelectionField(err error) (field string) {
+	for _, fieldError := range serverSelectionFields {
+		if errors.Is(err, fieldError.err) {
+			return fieldError.field
+		}
+	}
+	return ""
+}
+
+func (h *vpnHandler) getConnection(w http.ResponseWriter) {
+	connection, vpnInterface := h.looper.GetConnection()
+	settings := h.looper.GetSettings()
+	data := connectionWrapper{
+		Status:     string(h.looper.GetStatus()),
+		Type:       settings.Type,
+		Provider:   *settings.Provider.Name,
+		ServerName: connection.Hostname,
+		Protocol:   connection.Protocol,
+		Interface:  vpnInterface,
+	}
+	if connection.IP != nil {
+		data.Endpoint = net.JoinHostPort(connection.IP.String(),
+			strconv.Itoa(int(connection.Port)))
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getPortForwarded(w http.ResponseWriter) {
+	port := h.pf.GetPortForwarded()
+	encoder := json.NewEncoder(w)
+	data := portWrapper{Port: port}
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// rotate reconnects the VPN to a different server
+// matching the current server selection settings.
+func (h *vpnHandler) rotate(w http.ResponseWriter) {
+	outcome, err := h.looper.Rotate(h.ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getProfile(w http.ResponseWriter) {
+	name, names := h.looper.GetProfile()
+	data := profileWrapper{
+		Active:   name,
+		Profiles: names,
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getBlocklist(w http.ResponseWriter) {
+	entries := h.looper.GetBlocklist()
+	data := blocklistWrapper{
+		Entries: make([]blocklistEntryWrapper, len(entries)),
+	}
+	for i, entry := range entries {
+		data.Entries[i] = blocklistEntryWrapper{
+			Hostname: entry.Hostname,
+			Reason:   entry.Reason,
+			Until:    entry.Until,
+		}
+		if entry.IP != nil {
+			data.Entries[i].IP = entry.IP.String()
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) clearBlocklist(w http.ResponseWriter) {
+	err := h.looper.ClearBlocklist()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: "blocklist cleared"}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+const redacted = "redacted"
+
+// redactVPNSettings returns a copy of the VPN settings
+// with all credentials and secret keys redacted.
+func redactVPNSettings(vpnSettings settings.VPN) settings.VPN {
+	vpnSettings.OpenVPN = redactOpenVPNSettings(vpnSettings.OpenVPN)
+	vpnSettings.Wireguard.PrivateKey = redactStringPtr(vpnSettings.Wireguard.PrivateKey)
+	vpnSettings.Wireguard.PreSharedKey = redactStringPtr(vpnSettings.Wireguard.PreSharedKey)
+	vpnSettings.Provider.PortForwarding = redactPortForwardingSettings(
+		vpnSettings.Provider.PortForwarding)
+	if len(vpnSettings.Failover.Profiles) > 0 {
+		profiles := make([]settings.VPNProfile, len(vpnSettings.Failover.Profiles))
+		for i, profile := range vpnSettings.Failover.Profiles {
+			redactedSettings := redactVPNSettings(settings.VPN{
+				Provider:  profile.Provider,
+				OpenVPN:   profile.OpenVPN,
+				Wireguard: profile.Wireguard,
+			})
+			profile.Provider = redactedSettings.Provider
+			profile.OpenVPN = redactedSettings.OpenVPN
+			profile.Wireguard = redactedSettings.Wireguard
+			profiles[i] = profile
+		}
+		vpnSettings.Failover.Profiles = profiles
+	}
+	return vpnSettings
+}
+
+func redactPortForwardingSettings(
+	portForwardingSettings settings.PortForwarding) settings.PortForwarding {
+	portForwardingSettings.QBittorrent.Password = redactStringPtr(
+		portForwardingSettings.QBittorrent.Password)
+	portForwardingSettings.Transmission.Password = redactStringPtr(
+		portForwardingSettings.Transmission.Password)
+	return portForwardingSettings
+}
+
+func redactOpenVPNSettings(openvpnSettings settings.OpenVPN) settings.OpenVPN {
+	openvpnSettings.User = redacted
+	openvpnSettings.Password = redacted
+	openvpnSettings.ClientKey = redactStringPtr(openvpnSettings.ClientKey)
+	return openvpnSettings
+}
+
+// redactStringPtr returns a pointer to a new redacted string
+// if the string pointed is set. It does not modify the pointed
+// value, since it is shared with the original settings.
+func redactStringPtr(s *string) *string {
+	if s == nil || *s == "" {
+		return s
+	}
+	redactedString := redacted
+	return &redactedString
+}
+
+// unredactStringPtr returns nil if the string pointed is the
+// redacted placeholder, so a client sending back the settings it
+// got leaves the secret unchanged instead of setting it to the
+// placeholder.
+func unredactStringPtr(s *string) *string {
+	if s != nil && *s == redacted {
+		return nil
+	}
+	return s
+}
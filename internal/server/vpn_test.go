@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/vpn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_redactVPNSettings(t *testing.T) {
+	t.Parallel()
+
+	stringPtr := func(s string) *string { return &s }
+	original := settings.VPN{
+		Provider: settings.Provider{
+			PortForwarding: settings.PortForwarding{
+				QBittorrent: settings.PortForwardingClient{
+					Password: stringPtr("qbittorrent"),
+				},
+			},
+		},
+		OpenVPN: settings.OpenVPN{
+			User:      "user",
+			Password:  "password",
+			ClientKey: stringPtr("key"),
+			ClientCrt: stringPtr("crt"),
+		},
+		Wireguard: settings.Wireguard{
+			PrivateKey:   stringPtr("private"),
+			PreSharedKey: stringPtr(""),
+		},
+		Failover: settings.Failover{
+			Profiles: []settings.VPNProfile{{
+				Name: "backup",
+				OpenVPN: settings.OpenVPN{
+					User:     "backup-user",
+					Password: "backup-password",
+				},
+				Wireguard: settings.Wireguard{
+					PrivateKey: stringPtr("backup-private"),
+				},
+			}},
+		},
+	}
+
+	redactedSettings := redactVPNSettings(original)
+
+	assert.Equal(t, "redacted", redactedSettings.OpenVPN.User)
+	assert.Equal(t, "redacted", redactedSettings.OpenVPN.Password)
+	assert.Equal(t, "redacted", *redactedSettings.OpenVPN.ClientKey)
+	assert.Equal(t, "crt", *redactedSettings.OpenVPN.ClientCrt)
+	assert.Equal(t, "redacted", *redactedSettings.Wireguard.PrivateKey)
+	assert.Equal(t, "", *redactedSettings.Wireguard.PreSharedKey)
+	assert.Equal(t, "redacted", *redactedSettings.Provider.PortForwarding.QBittorrent.Password)
+	assert.Nil(t, redactedSettings.Provider.PortForwarding.Transmission.Password)
+	redactedProfile := redactedSettings.Failover.Profiles[0]
+	assert.Equal(t, "backup", redactedProfile.Name)
+	assert.Equal(t, "redacted", redactedProfile.OpenVPN.User)
+	assert.Equal(t, "redacted", redactedProfile.OpenVPN.Password)
+	assert.Equal(t, "redacted", *redactedProfile.Wireguard.PrivateKey)
+
+	// Original settings pointed values must be left untouched
+	assert.Equal(t, "key", *original.OpenVPN.ClientKey)
+	assert.Equal(t, "private", *original.Wireguard.PrivateKey)
+	assert.Equal(t, "qbittorrent", *original.Provider.PortForwarding.QBittorrent.Password)
+	assert.Equal(t, "backup-user", original.Failover.Profiles[0].OpenVPN.User)
+}
+
+type testVPNLooper struct {
+	vpn.Looper
+	settings settings.VPN
+	servers  models.AllServers
+}
+
+func (t *testVPNLooper) GetSettings() settings.VPN { return t.settings }
+
+func (t *testVPNLooper) GetServers() models.AllServers { return t.servers }
+
+func (t *testVPNLooper) SetSettings(_ context.Context, vpnSettings settings.VPN) string {
+	t.settings = vpnSettings
+	return "settings updated"
+}
+
+func Test_vpnHandler_setSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body            string
+		initialRegions  []string
+		status          int
+		responseBody    string
+		expectedRegions []string
+	}{
+		"unknown field": {
+			body:   `{"Region":"DE Berlin"}`,
+			status: http.StatusBadRequest,
+			responseBody: `{"error":"cannot decode server selection: ` +
+				`json: unknown field \"Region\""}` + "\n",
+		},
+		"region not valid": {
+			body:   `{"Regions":["Atlantis"]}`,
+			status: http.StatusBadRequest,
+			responseBody: `{"error":"provider settings: server selection: ` +
+				`the region specified is not valid: value is not one of the ` +
+				`possible choices: value \"atlantis\", choices available ` +
+				`are DE Berlin","field":"Regions"}` + "\n",
+		},
+		"set regions": {
+			body:            `{"Regions":["DE Berlin"]}`,
+			status:          http.StatusOK,
+			responseBody:    `{"outcome":"settings updated"}` + "\n",
+			expectedRegions: []string{"de berlin"},
+		},
+		"absent field left unchanged": {
+			body:            `{"Strategy":"random"}`,
+			initialRegions:  []string{"de berlin"},
+			status:          http.StatusOK,
+			responseBody:    `{"outcome":"settings updated"}` + "\n",
+			expectedRegions: []string{"de berlin"},
+		},
+		"null field left unchanged": {
+			body:            `{"Regions":null}`,
+			initialRegions:  []string{"de berlin"},
+			status:          http.StatusOK,
+			responseBody:    `{"outcome":"settings updated"}` + "\n",
+			expectedRegions: []string{"de berlin"},
+		},
+		"empty array clears filter": {
+			body:            `{"Regions":[]}`,
+			initialRegions:  []string{"de berlin"},
+			status:          http.StatusOK,
+			responseBody:    `{"outcome":"settings updated"}` + "\n",
+			expectedRegions: []string{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var allSettings settings.Settings
+			allSettings.SetDefaults()
+			vpnSettings := allSettings.VPN
+			vpnSettings.OpenVPN.User = "user"
+			vpnSettings.OpenVPN.Password = "password"
+			vpnSettings.Provider.ServerSelection.Regions = testCase.initialRegions
+			looper := &testVPNLooper{
+				settings: vpnSettings,
+				servers: models.AllServers{
+					Pia: models.Servers{
+						Servers: []models.Server{
+							{Country: "Germany", Region: "DE Berlin", UDP: true},
+						},
+					},
+				},
+			}
+			handler := newVPNHandler(context.Background(), looper, nil, nil)
+
+			request := httptest.NewRequest(http.MethodPut, "/vpn/settings",
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.responseBody, recorder.Body.String())
+			if testCase.status == http.StatusOK {
+				assert.Equal(t, testCase.expectedRegions,
+					looper.settings.Provider.ServerSelection.Regions)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package server
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/logs"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -32,3 +34,103 @@ type portWrapper struct {
 type outcomeWrapper struct {
 	Outcome string `json:"outcome"`
 }
+
+type errorWrapper struct {
+	Error string `json:"error"`
+	// Field is the settings field at fault, if known.
+	Field string `json:"field,omitempty"`
+}
+
+type connectionWrapper struct {
+	Status     string `json:"status"`
+	Type       string `json:"type"`
+	Provider   string `json:"provider"`
+	ServerName string `json:"server_name,omitempty"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+	Interface  string `json:"interface,omitempty"`
+}
+
+type serversWrapper struct {
+	Provider     string              `json:"provider"`
+	Page         int                 `json:"page"`
+	PerPage      int                 `json:"per_page"`
+	Total        int                 `json:"total"`
+	Servers      []models.Server     `json:"servers"`
+	FilterValues serversFilterValues `json:"filter_values"`
+}
+
+type serversFilterValues struct {
+	Countries []string `json:"countries"`
+	Regions   []string `json:"regions"`
+	Cities    []string `json:"cities"`
+	ISPs      []string `json:"isps"`
+	Names     []string `json:"names"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type firewallStateWrapper struct {
+	Enabled         bool               `json:"enabled"`
+	VPNInterface    string             `json:"vpn_interface,omitempty"`
+	OutboundSubnets []string           `json:"outbound_subnets"`
+	InputPorts      []inputPortWrapper `json:"input_ports"`
+}
+
+type inputPortWrapper struct {
+	Port       uint16   `json:"port"`
+	Interfaces []string `json:"interfaces"`
+}
+
+type inputPortChangeWrapper struct {
+	Port      uint16 `json:"port"`
+	Interface string `json:"interface,omitempty"`
+}
+
+type outboundSubnetWrapper struct {
+	Subnet string `json:"subnet"`
+}
+
+type logsWrapper struct {
+	Lines []logs.Line `json:"lines"`
+}
+
+type healthWrapper struct {
+	Healthy  bool            `json:"healthy"`
+	Error    string          `json:"error,omitempty"`
+	Probes   []probeWrapper  `json:"probes"`
+	Recovery recoveryWrapper `json:"recovery"`
+}
+
+type profileWrapper struct {
+	Active   string   `json:"active"`
+	Profiles []string `json:"profiles"`
+}
+
+type blocklistWrapper struct {
+	Entries []blocklistEntryWrapper `json:"entries"`
+}
+
+type blocklistEntryWrapper struct {
+	IP       string    `json:"ip,omitempty"`
+	Hostname string    `json:"hostname,omitempty"`
+	Reason   string    `json:"reason"`
+	Until    time.Time `json:"until"`
+}
+
+type recoveryWrapper struct {
+	Attempts       uint       `json:"attempts"`
+	Restarts       uint       `json:"restarts"`
+	ServerSwitches uint       `json:"server_switches"`
+	TCPFallback    bool       `json:"tcp_fallback"`
+	LastStep       string     `json:"last_step,omitempty"`
+	LastOutcome    string     `json:"last_outcome,omitempty"`
+	LastTime       *time.Time `json:"last_time,omitempty"`
+}
+
+type probeWrapper struct {
+	Type      string  `json:"type"`
+	Address   string  `json:"address"`
+	Healthy   bool    `json:"healthy"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
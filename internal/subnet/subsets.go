@@ -42,6 +42,16 @@ func findSubnetsToRemove(oldSubnets, newSubnets []net.IPNet) (subnetsToRemove []
 	return subnetsToRemove
 }
 
+// SubnetInSubnets returns true if the subnet is in the subnets given.
+func SubnetInSubnets(subnet net.IPNet, subnets []net.IPNet) bool {
+	for i := range subnets {
+		if subnetsAreEqual(subnet, subnets[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 func RemoveSubnetFromSubnets(subnets []net.IPNet, subnet net.IPNet) []net.IPNet {
 	L := len(subnets)
 	for i := range subnets {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/storage"
 )
@@ -29,6 +30,7 @@ type looper struct {
 	flusher       storage.Flusher
 	setAllServers func(allServers models.AllServers)
 	logger        infoErrorer
+	publisher     events.Publisher
 	// Internal channels and locks
 	loopLock     sync.Mutex
 	start        chan struct{}
@@ -46,7 +48,7 @@ const defaultBackoffTime = 5 * time.Second
 
 func NewLooper(settings settings.Updater, currentServers models.AllServers,
 	flusher storage.Flusher, setAllServers func(allServers models.AllServers),
-	client *http.Client, logger Logger) Looper {
+	client *http.Client, logger Logger, publisher events.Publisher) Looper {
 	return &looper{
 		state: state{
 			status:   constants.Stopped,
@@ -56,6 +58,7 @@ func NewLooper(settings settings.Updater, currentServers models.AllServers,
 		flusher:       flusher,
 		setAllServers: setAllServers,
 		logger:        logger,
+		publisher:     publisher,
 		start:         make(chan struct{}),
 		running:       make(chan models.LoopStatus),
 		stop:          make(chan struct{}),
@@ -145,6 +148,7 @@ func (l *looper) Run(ctx context.Context, done chan<- struct{}) {
 				runWg.Wait()
 				l.state.setStatusWithLock(constants.Completed)
 				l.logger.Info("Updated servers information")
+				l.publisher.Publish("updater", events.TypeUpdaterCompleted, nil)
 			case err := <-errorCh:
 				close(serversCh)
 				runWg.Wait()
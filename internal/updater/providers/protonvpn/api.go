@@ -22,9 +22,14 @@ type logicalServer struct {
 	ExitCountry string
 	Region      *string
 	City        *string
+	Features    uint16
 	Servers     []physicalServer
 }
 
+// featureP2P is the bit set in the logical server features
+// if the server supports P2P and hence port forwarding.
+const featureP2P = 4
+
 type physicalServer struct {
 	EntryIP net.IP
 	ExitIP  net.IP
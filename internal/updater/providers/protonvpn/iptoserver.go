@@ -9,7 +9,7 @@ import (
 type ipToServer map[string]models.Server
 
 func (its ipToServer) add(country, region, city, name, hostname string,
-	entryIP net.IP) {
+	entryIP net.IP, portForward bool) {
 	key := entryIP.String()
 
 	server, ok := its[key]
@@ -19,6 +19,7 @@ func (its ipToServer) add(country, region, city, name, hostname string,
 		server.City = city
 		server.ServerName = name
 		server.Hostname = hostname
+		server.PortForward = portForward
 		server.IPs = []net.IP{entryIP}
 	} else {
 		server.IPs = append(server.IPs, entryIP)
@@ -38,6 +38,7 @@ func GetServers(ctx context.Context, client *http.Client, minServers int) (
 		region := getStringValue(logicalServer.Region)
 		city := getStringValue(logicalServer.City)
 		name := logicalServer.Name
+		portForward := logicalServer.Features&featureP2P != 0
 		for _, physicalServer := range logicalServer.Servers {
 			if physicalServer.Status == 0 { // disabled so skip server
 				warnings = append(warnings,
@@ -56,7 +57,7 @@ func GetServers(ctx context.Context, client *http.Client, minServers int) (
 				warnings = append(warnings, warning)
 			}
 
-			ipToServer.add(country, region, city, name, hostname, entryIP)
+			ipToServer.add(country, region, city, name, hostname, entryIP, portForward)
 		}
 	}
 
@@ -0,0 +1,46 @@
+package vpn
+
+import (
+	"github.com/qdm12/gluetun/internal/blocklist"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type Blocker interface {
+	BlockCurrentServer(reason string) (err error)
+	GetBlocklist() (entries []blocklist.Entry)
+	ClearBlocklist() (err error)
+}
+
+// BlockCurrentServer excludes the current server from the server
+// selection for the blocklist cooldown duration. It does nothing
+// if the blocklist cooldown is 0.
+func (l *Loop) BlockCurrentServer(reason string) (err error) {
+	connection, _ := l.state.GetConnection()
+	if connection.IP == nil {
+		return ErrNotRunning
+	}
+	return l.blockServer(connection, reason)
+}
+
+func (l *Loop) GetBlocklist() (entries []blocklist.Entry) {
+	return l.blocklist.List()
+}
+
+func (l *Loop) ClearBlocklist() (err error) {
+	return l.blocklist.Clear()
+}
+
+func (l *Loop) blockServer(connection models.Connection, reason string) (err error) {
+	cooldown := *l.GetSettings().BlocklistCooldown
+	if cooldown == 0 {
+		return nil
+	}
+
+	server := connection.Hostname
+	if server == "" {
+		server = connection.IP.String()
+	}
+	l.logger.Warn("blocking server " + server + " for " + cooldown.String() + ": " + reason)
+
+	return l.blocklist.Add(connection, reason, cooldown)
+}
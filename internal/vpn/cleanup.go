@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/publicip/models"
 )
 
@@ -17,6 +18,13 @@ func (l *Loop) cleanup(ctx context.Context, pfEnabled bool) {
 
 	l.publicip.SetData(models.IPInfoData{}) // clear public IP address data
 
+	connection, vpnInterface := l.state.GetConnection()
+	if connection.IP != nil {
+		l.publisher.Publish("vpn", events.TypeTunnelDown,
+			tunnelData(connection, vpnInterface))
+	}
+	l.clearConnection()
+
 	if pfEnabled {
 		const pfTimeout = 100 * time.Millisecond
 		err := l.stopPortForwarding(ctx, pfTimeout)
@@ -0,0 +1,15 @@
+package vpn
+
+import "github.com/qdm12/gluetun/internal/models"
+
+type ConnectionGetter interface {
+	GetConnection() (connection models.Connection, vpnInterface string)
+}
+
+func (l *Loop) GetConnection() (connection models.Connection, vpnInterface string) {
+	return l.state.GetConnection()
+}
+
+func (l *Loop) clearConnection() {
+	l.state.SetConnection(models.Connection{}, "")
+}
@@ -0,0 +1,160 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+type Failoverer interface {
+	FailOver(ctx context.Context) (outcome string, err error)
+	ReportHealthy()
+	GetProfile() (name string, names []string)
+}
+
+var ErrFailoverNotDue = errors.New("failover is not due")
+
+// FailOver records the current VPN profile as failing and, if it
+// has been failing for longer than the failover threshold, switches
+// to the next VPN profile and restarts the VPN.
+func (l *Loop) FailOver(ctx context.Context) (outcome string, err error) {
+	vpnSettings := l.GetSettings()
+	if len(vpnSettings.Failover.Profiles) == 0 {
+		return "", fmt.Errorf("%w: no failover profile", ErrFailoverNotDue)
+	}
+
+	failing, due := l.profileFailing(vpnSettings)
+	if !due {
+		return "", fmt.Errorf("%w: profile failing for %s only",
+			ErrFailoverNotDue, failing.Round(time.Second))
+	}
+
+	l.nextProfile(vpnSettings)
+	_, _ = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	outcome, _ = l.statusManager.ApplyStatus(ctx, constants.Running)
+	return outcome, nil
+}
+
+// ReportHealthy records the current VPN profile as working,
+// and saves the current connection as the last known good one.
+func (l *Loop) ReportHealthy() {
+	l.state.ResetProfileFailing()
+	l.saveLastConnection()
+}
+
+// GetProfile returns the name of the VPN profile in use,
+// and the names of all the VPN profiles in order.
+func (l *Loop) GetProfile() (name string, names []string) {
+	names = l.GetSettings().ProfileNames()
+	index := l.state.GetProfileIndex()
+	if index >= len(names) {
+		index = 0
+	}
+	return names[index], names
+}
+
+// failOverIfDue is used when the VPN fails to connect and switches
+// to the next VPN profile if the current profile has been failing
+// for longer than the failover threshold. The Run loop picks up
+// the new profile on its next iteration.
+func (l *Loop) failOverIfDue() {
+	vpnSettings := l.GetSettings()
+	if len(vpnSettings.Failover.Profiles) == 0 {
+		return
+	}
+
+	if _, due := l.profileFailing(vpnSettings); due {
+		l.nextProfile(vpnSettings)
+	}
+}
+
+// profileFailing records the current VPN profile as failing, and
+// returns for how long it has been failing and whether this is
+// longer than the failover threshold.
+func (l *Loop) profileFailing(vpnSettings settings.VPN) (
+	failing time.Duration, due bool) {
+	now := time.Now()
+	since := l.state.SetProfileFailing(now)
+	failing = now.Sub(since)
+	return failing, failing >= *vpnSettings.Failover.Threshold
+}
+
+func (l *Loop) nextProfile(vpnSettings settings.VPN) {
+	names := vpnSettings.ProfileNames()
+	index := l.state.GetProfileIndex()
+	if index >= len(names) {
+		index = 0
+	}
+	nextIndex := (index + 1) % len(names)
+	l.logger.Warn("failing over from VPN profile " + names[index] +
+		" to VPN profile " + names[nextIndex])
+	l.state.SetProfileIndex(nextIndex)
+}
+
+// applyProfile returns the VPN settings with the connection
+// settings of the VPN profile in use, and logs this profile.
+func (l *Loop) applyProfile(vpnSettings settings.VPN) settings.VPN {
+	if len(vpnSettings.Failover.Profiles) == 0 {
+		return vpnSettings
+	}
+	vpnSettings, name := l.currentProfile(vpnSettings)
+	l.logger.Info("using VPN profile " + name)
+	return vpnSettings
+}
+
+// currentProfile returns the VPN settings with the connection
+// settings of the VPN profile in use, and the profile name.
+func (l *Loop) currentProfile(vpnSettings settings.VPN) (
+	profileSettings settings.VPN, name string) {
+	index := l.state.GetProfileIndex()
+	profiles := vpnSettings.Failover.Profiles
+	if index == 0 || index > len(profiles) {
+		return vpnSettings, settings.PrimaryProfileName
+	}
+	profile := profiles[index-1]
+	return vpnSettings.WithProfile(profile), profile.Name
+}
+
+type FailbackTickerRunner interface {
+	RunFailbackTicker(ctx context.Context, done chan<- struct{})
+}
+
+// RunFailbackTicker periodically tries to return to the primary
+// VPN profile if another profile is in use, if the failover
+// return period setting is set.
+func (l *Loop) RunFailbackTicker(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	vpnSettings := l.GetSettings()
+	if len(vpnSettings.Failover.Profiles) == 0 ||
+		*vpnSettings.Failover.ReturnPeriod == 0 {
+		return
+	}
+
+	timer := time.NewTimer(*vpnSettings.Failover.ReturnPeriod)
+	for {
+		select {
+		case <-ctx.Done():
+			if !timer.Stop() {
+				<-timer.C
+			}
+			return
+		case <-timer.C:
+			if l.state.GetProfileIndex() != 0 && l.GetStatus() == constants.Running {
+				l.logger.Info("trying to return to VPN profile " + settings.PrimaryProfileName)
+				l.state.SetProfileIndex(0)
+				_, _ = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+				_, _ = l.statusManager.ApplyStatus(ctx, constants.Running)
+			}
+			vpnSettings := l.GetSettings()
+			if *vpnSettings.Failover.ReturnPeriod == 0 {
+				return
+			}
+			timer.Reset(*vpnSettings.Failover.ReturnPeriod)
+		}
+	}
+}
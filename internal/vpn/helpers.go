@@ -31,6 +31,7 @@ func (l *Loop) waitForError(ctx context.Context,
 }
 
 func (l *Loop) crashed(ctx context.Context, err error) {
+	l.failOverIfDue()
 	l.signalOrSetStatus(constants.Crashed)
 	l.logAndWait(ctx, err)
 }
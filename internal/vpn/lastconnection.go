@@ -0,0 +1,169 @@
+package vpn
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// lastConnection is the last connection which reached
+// a healthy state, persisted across restarts.
+type lastConnection struct {
+	Provider   string            `json:"provider"`
+	Connection models.Connection `json:"connection"`
+}
+
+type lastConnectionStore struct {
+	filepath string
+	saved    lastConnection
+	mutex    sync.Mutex
+}
+
+func newLastConnectionStore(filepath string) *lastConnectionStore {
+	return &lastConnectionStore{
+		filepath: filepath,
+	}
+}
+
+// read returns the last connection saved to file, which is
+// empty if no connection was saved.
+func (s *lastConnectionStore) read() (last lastConnection, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := os.ReadFile(s.filepath)
+	if os.IsNotExist(err) {
+		return last, nil
+	} else if err != nil {
+		return last, fmt.Errorf("cannot read last connection file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &last)
+	if err != nil {
+		return last, fmt.Errorf("cannot decode last connection file: %w", err)
+	}
+
+	s.saved = last
+	return last, nil
+}
+
+// save writes the last connection to file, only if it
+// changed since it was last read or saved.
+func (s *lastConnectionStore) save(last lastConnection) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.saved.Provider == last.Provider &&
+		s.saved.Connection.Type == last.Connection.Type &&
+		s.saved.Connection.Equal(last.Connection) {
+		return nil
+	}
+
+	const dirPermission = 0700
+	err = os.MkdirAll(filepath.Dir(s.filepath), dirPermission)
+	if err != nil {
+		return fmt.Errorf("cannot create last connection directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(last, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode last connection: %w", err)
+	}
+
+	const filePermission = 0600
+	err = os.WriteFile(s.filepath, data, filePermission)
+	if err != nil {
+		return fmt.Errorf("cannot write last connection file: %w", err)
+	}
+
+	s.saved = last
+	return nil
+}
+
+// saveLastConnection saves the current connection as the last
+// known good connection, if the VPN is connected.
+func (l *Loop) saveLastConnection() {
+	connection, _ := l.state.GetConnection()
+	if connection.IP == nil {
+		return
+	}
+
+	vpnSettings, _ := l.currentProfile(l.GetSettings())
+	err := l.lastGood.save(lastConnection{
+		Provider:   *vpnSettings.Provider.Name,
+		Connection: connection,
+	})
+	if err != nil {
+		l.logger.Warn("cannot save last known good connection: " + err.Error())
+	}
+}
+
+// preferLastConnection returns the servers data where the servers
+// of the VPN provider are restricted to the server of the last known
+// good connection, if this one is not blocked and still matches the
+// server selection.
+// Otherwise, the servers data is returned unchanged.
+func (l *Loop) preferLastConnection(allServers models.AllServers,
+	vpnSettings settings.VPN) models.AllServers {
+	last, err := l.lastGood.read()
+	if err != nil {
+		l.logger.Warn(err.Error())
+		return allServers
+	}
+
+	if last.Connection.IP == nil ||
+		last.Provider != *vpnSettings.Provider.Name ||
+		last.Connection.Type != vpnSettings.Type ||
+		allServers.ServersByProvider(last.Provider) == nil {
+		return allServers
+	}
+
+	server := last.Connection.IP.String()
+	if last.Connection.Hostname != "" {
+		server = last.Connection.Hostname
+	}
+
+	if l.blocklist.Blocked(last.Connection) {
+		l.logger.Info("last known good server " + server + " is blocked")
+		return allServers
+	}
+
+	filtered, err := filterServers(allServers, vpnSettings,
+		keepConnection(last.Connection))
+	if err != nil {
+		l.logger.Info("last known good server " + server +
+			" no longer matches the server selection: " + err.Error())
+		return allServers
+	}
+
+	l.logger.Info("trying last known good server " + server + " first")
+	return filtered
+}
+
+// keepConnection returns a filter function keeping only the server
+// corresponding to the connection given, restricted to the connection
+// IP address if this one belongs to the server.
+func keepConnection(connection models.Connection) func(servers []models.Server) []models.Server {
+	return func(servers []models.Server) (filtered []models.Server) {
+		filtered = make([]models.Server, 0, 1)
+		for _, server := range servers {
+			if !serverHasConnection(server, connection) {
+				continue
+			}
+			for _, ip := range server.IPs {
+				if ip.Equal(connection.IP) {
+					server.IPs = []net.IP{ip}
+					break
+				}
+			}
+			filtered = append(filtered, server)
+		}
+		return filtered
+	}
+}
@@ -0,0 +1,92 @@
+package vpn
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lastConnectionStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sub", "lastconnection.json")
+	store := newLastConnectionStore(path)
+
+	last, err := store.read()
+	require.NoError(t, err)
+	assert.Equal(t, lastConnection{}, last)
+
+	saved := lastConnection{
+		Provider: "mullvad",
+		Connection: models.Connection{
+			Type:     "wireguard",
+			IP:       net.IPv4(1, 2, 3, 4),
+			Port:     51820,
+			Protocol: "udp",
+			Hostname: "se1-wireguard",
+			PubKey:   "key",
+		},
+	}
+	err = store.save(saved)
+	require.NoError(t, err)
+
+	dirInfo, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+
+	last, err = newLastConnectionStore(path).read()
+	require.NoError(t, err)
+	assert.Equal(t, saved, last)
+}
+
+func Test_keepConnection(t *testing.T) {
+	t.Parallel()
+
+	servers := []models.Server{
+		{Hostname: "a.com", IPs: []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}}},
+		{Hostname: "b.com", IPs: []net.IP{{3, 3, 3, 3}}},
+	}
+
+	testCases := map[string]struct {
+		connection models.Connection
+		filtered   []models.Server
+	}{
+		"matching IP address": {
+			connection: models.Connection{IP: net.IP{2, 2, 2, 2}},
+			filtered: []models.Server{
+				{Hostname: "a.com", IPs: []net.IP{{2, 2, 2, 2}}},
+			},
+		},
+		"matching hostname only": {
+			connection: models.Connection{IP: net.IP{9, 9, 9, 9}, Hostname: "b.com"},
+			filtered: []models.Server{
+				{Hostname: "b.com", IPs: []net.IP{{3, 3, 3, 3}}},
+			},
+		},
+		"no match": {
+			connection: models.Connection{IP: net.IP{9, 9, 9, 9}},
+			filtered:   []models.Server{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := keepConnection(testCase.connection)(servers)
+
+			assert.Equal(t, testCase.filtered, filtered)
+			// Original servers must be left untouched
+			assert.Len(t, servers[0].IPs, 2)
+		})
+	}
+}
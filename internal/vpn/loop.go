@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/blocklist"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/latency"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
@@ -28,6 +31,13 @@ type Looper interface {
 	loopstate.Applier
 	SettingsGetSetter
 	ServersGetterSetter
+	ConnectionGetter
+	Rotator
+	RotationTickerRunner
+	Recoverer
+	Blocker
+	Failoverer
+	FailbackTickerRunner
 }
 
 type Loop struct {
@@ -45,6 +55,14 @@ type Loop struct {
 	portForward portforward.StartStopper
 	publicip    publicip.Looper
 	dnsLooper   dns.Looper
+	blocklist   *blocklist.Blocklist
+	publisher   events.Publisher
+	// latencyProber measures the latency to servers for
+	// the server selection strategies based on latency.
+	latencyProber *latency.Prober
+	// lastGood persists the last connection
+	// which reached a healthy state.
+	lastGood *lastConnectionStore
 	// Other objects
 	starter command.Starter // for OpenVPN
 	logger  log.LoggerInterface
@@ -62,6 +80,7 @@ type Loop struct {
 type firewallConfigurer interface {
 	firewall.VPNConnectionSetter
 	firewall.PortAllower
+	firewall.ProbeAllower
 }
 
 const (
@@ -72,15 +91,17 @@ func NewLoop(vpnSettings settings.VPN, vpnInputPorts []uint16,
 	allServers models.AllServers, openvpnConf openvpn.Interface,
 	netLinker netlink.NetLinker, fw firewallConfigurer, routing routing.VPNGetter,
 	portForward portforward.StartStopper, starter command.Starter,
-	publicip publicip.Looper, dnsLooper dns.Looper,
+	publicip publicip.Looper, dnsLooper dns.Looper, blocklist *blocklist.Blocklist,
 	logger log.LoggerInterface, client *http.Client,
-	buildInfo models.BuildInformation, versionInfo bool) *Loop {
+	buildInfo models.BuildInformation, versionInfo bool,
+	publisher events.Publisher) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped,
+		"vpn", publisher)
 	state := state.New(statusManager, vpnSettings, allServers)
 
 	return &Loop{
@@ -96,6 +117,10 @@ func NewLoop(vpnSettings settings.VPN, vpnInputPorts []uint16,
 		portForward:   portForward,
 		publicip:      publicip,
 		dnsLooper:     dnsLooper,
+		blocklist:     blocklist,
+		publisher:     publisher,
+		latencyProber: latency.New(fw, logger.New(log.SetComponent("latency"))),
+		lastGood:      newLastConnectionStore(constants.LastConnection),
 		starter:       starter,
 		logger:        logger,
 		client:        client,
@@ -3,46 +3,66 @@ package vpn
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/golibs/command"
 )
 
 // setupOpenVPN sets OpenVPN up using the configurators and settings given.
-// It returns a serverName for port forwarding (PIA) and an error if it fails.
+// It returns the connection chosen and an error if it fails.
 func setupOpenVPN(ctx context.Context, fw firewall.VPNConnectionSetter,
 	openvpnConf openvpn.Interface, providerConf provider.Provider,
 	settings settings.VPN, starter command.Starter, logger openvpn.Logger) (
-	runner vpnRunner, serverName string, err error) {
-	connection, err := providerConf.GetConnection(settings.Provider.ServerSelection)
+	runner vpnRunner, connection models.Connection, err error) {
+	connection, err = providerConf.GetConnection(settings.Provider.ServerSelection)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed finding a valid server connection: %w", err)
+		return nil, connection, fmt.Errorf("failed finding a valid server connection: %w", err)
 	}
 
 	lines, err := providerConf.BuildConf(connection, settings.OpenVPN)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed building configuration: %w", err)
+		return nil, connection, fmt.Errorf("failed building configuration: %w", err)
 	}
 
 	if err := openvpnConf.WriteConfig(lines); err != nil {
-		return nil, "", fmt.Errorf("failed writing configuration to file: %w", err)
+		return nil, connection, fmt.Errorf("failed writing configuration to file: %w", err)
 	}
 
 	if settings.OpenVPN.User != "" {
-		err := openvpnConf.WriteAuthFile(settings.OpenVPN.User, settings.OpenVPN.Password)
+		err := openvpnConf.WriteAuthFile(openvpnUser(settings), settings.OpenVPN.Password)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed writing auth to file: %w", err)
+			return nil, connection, fmt.Errorf("failed writing auth to file: %w", err)
 		}
 	}
 
 	if err := fw.SetVPNConnection(ctx, connection, settings.OpenVPN.Interface); err != nil {
-		return nil, "", fmt.Errorf("failed allowing VPN connection through firewall: %w", err)
+		return nil, connection, fmt.Errorf("failed allowing VPN connection through firewall: %w", err)
 	}
 
-	runner = openvpn.NewRunner(settings.OpenVPN, starter, logger)
+	// Stop OpenVPN on TLS errors to block the server and
+	// connect to another one, if the blocklist is enabled.
+	exitOnTLSError := *settings.BlocklistCooldown > 0
+	runner = openvpn.NewRunner(settings.OpenVPN, starter, logger, exitOnTLSError)
 
-	return runner, connection.Hostname, nil
+	return runner, connection, nil
+}
+
+// openvpnUser returns the username to write to the OpenVPN auth file.
+// ProtonVPN servers only accept NAT-PMP port mapping requests if the
+// username ends with +pmp.
+func openvpnUser(settings settings.VPN) (user string) {
+	user = settings.OpenVPN.User
+	const protonvpnPortForwardSuffix = "+pmp"
+	if *settings.Provider.Name == providers.Protonvpn &&
+		*settings.Provider.PortForwarding.Enabled &&
+		!strings.HasSuffix(user, protonvpnPortForwardSuffix) {
+		user += protonvpnPortForwardSuffix
+	}
+	return user
 }
@@ -0,0 +1,108 @@
+package vpn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	provider.Provider
+}
+
+func (p *testProvider) GetConnection(settings.ServerSelection) (
+	connection models.Connection, err error) {
+	return models.Connection{Hostname: "a.com"}, nil
+}
+
+func (p *testProvider) BuildConf(models.Connection, settings.OpenVPN) (
+	lines []string, err error) {
+	return []string{"client"}, nil
+}
+
+type testOpenVPNConf struct {
+	openvpn.Interface
+	user, password string
+}
+
+func (c *testOpenVPNConf) WriteConfig([]string) error { return nil }
+
+func (c *testOpenVPNConf) WriteAuthFile(user, password string) error {
+	c.user, c.password = user, password
+	return nil
+}
+
+type testFirewall struct {
+	firewall.VPNConnectionSetter
+}
+
+func (f *testFirewall) SetVPNConnection(context.Context,
+	models.Connection, string) error {
+	return nil
+}
+
+func Test_setupOpenVPN_authFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider       string
+		portForwarding bool
+		user           string
+		writtenUser    string
+	}{
+		"protonvpn without port forwarding": {
+			provider:    providers.Protonvpn,
+			user:        "user",
+			writtenUser: "user",
+		},
+		"protonvpn with port forwarding": {
+			provider:       providers.Protonvpn,
+			portForwarding: true,
+			user:           "user",
+			writtenUser:    "user+pmp",
+		},
+		"protonvpn with port forwarding and suffixed user": {
+			provider:       providers.Protonvpn,
+			portForwarding: true,
+			user:           "user+pmp",
+			writtenUser:    "user+pmp",
+		},
+		"private internet access with port forwarding": {
+			provider:       providers.PrivateInternetAccess,
+			portForwarding: true,
+			user:           "user",
+			writtenUser:    "user",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var allSettings settings.Settings
+			allSettings.SetDefaults()
+			vpnSettings := allSettings.VPN
+			vpnSettings.Provider.Name = &testCase.provider
+			vpnSettings.Provider.PortForwarding.Enabled = &testCase.portForwarding
+			vpnSettings.OpenVPN.User = testCase.user
+			vpnSettings.OpenVPN.Password = "password"
+			openvpnConf := &testOpenVPNConf{}
+
+			_, _, err := setupOpenVPN(context.Background(), &testFirewall{},
+				openvpnConf, &testProvider{}, vpnSettings, nil, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.writtenUser, openvpnConf.user)
+			assert.Equal(t, "password", openvpnConf.password)
+		})
+	}
+}
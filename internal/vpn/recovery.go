@@ -0,0 +1,81 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+type Recoverer interface {
+	SwitchServer(ctx context.Context, excludeFor time.Duration) (outcome string, err error)
+	FallbackToTCP(ctx context.Context) (outcome string, err error)
+}
+
+// SwitchServer excludes the current server from the server selection
+// for the duration given and restarts the VPN, such that it connects
+// to another server matching the server selection.
+func (l *Loop) SwitchServer(ctx context.Context, excludeFor time.Duration) (
+	outcome string, err error) {
+	connection, _ := l.state.GetConnection()
+	if connection.IP == nil {
+		return "", fmt.Errorf("%w: no current server to switch from", ErrNotRunning)
+	}
+
+	err = l.blocklist.Add(connection, "unhealthy", excludeFor)
+	if err != nil {
+		l.logger.Error("cannot add server to blocklist: " + err.Error())
+	}
+	_, _ = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	outcome, _ = l.statusManager.ApplyStatus(ctx, constants.Running)
+	return outcome, nil
+}
+
+var ErrTCPFallbackNotApplicable = errors.New("TCP fallback is not applicable")
+
+// FallbackToTCP makes OpenVPN use TCP instead of UDP until the VPN
+// settings are changed, and restarts the VPN.
+func (l *Loop) FallbackToTCP(ctx context.Context) (outcome string, err error) {
+	vpnSettings, allServers := l.state.GetSettingsAndServers()
+	switch {
+	case vpnSettings.Type != constants.OpenVPN:
+		return "", fmt.Errorf("%w: VPN type is %s", ErrTCPFallbackNotApplicable, vpnSettings.Type)
+	case *vpnSettings.Provider.ServerSelection.OpenVPN.TCP, l.state.GetTCPFallback():
+		return "", fmt.Errorf("%w: already using TCP", ErrTCPFallbackNotApplicable)
+	}
+
+	// Check a TCP connection can be picked with the server selection.
+	tcpSettings := withTCP(vpnSettings)
+	providerConf := provider.New(*tcpSettings.Provider.Name, allServers, nil, nil, time.Now)
+	_, err = providerConf.GetConnection(tcpSettings.Provider.ServerSelection)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrTCPFallbackNotApplicable, err)
+	}
+
+	l.state.SetTCPFallback(true)
+	_, _ = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	outcome, _ = l.statusManager.ApplyStatus(ctx, constants.Running)
+	return outcome, nil
+}
+
+// applyTCPFallback returns the VPN settings with the OpenVPN
+// protocol set to TCP if the TCP fallback is enabled.
+func (l *Loop) applyTCPFallback(vpnSettings settings.VPN) settings.VPN {
+	if vpnSettings.Type != constants.OpenVPN || !l.state.GetTCPFallback() {
+		return vpnSettings
+	}
+	l.logger.Info("using TCP instead of UDP as recovery fallback")
+	return withTCP(vpnSettings)
+}
+
+func withTCP(vpnSettings settings.VPN) settings.VPN {
+	// The TCP pointer is replaced and not modified in
+	// place, since it is shared with the state settings.
+	tcp := true
+	vpnSettings.Provider.ServerSelection.OpenVPN.TCP = &tcp
+	return vpnSettings
+}
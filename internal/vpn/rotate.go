@@ -0,0 +1,137 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+type Rotator interface {
+	Rotate(ctx context.Context) (outcome string, err error)
+}
+
+var ErrNotRunning = errors.New("VPN is not running")
+
+// Rotate reconnects the VPN to a different server matching the
+// current server selection, going through the stop and start flow
+// of the Run loop. It returns an error if the VPN is not running.
+func (l *Loop) Rotate(ctx context.Context) (outcome string, err error) {
+	status := l.GetStatus()
+	if status != constants.Running {
+		return "", fmt.Errorf("%w: status is %s", ErrNotRunning, status)
+	}
+
+	connection, _ := l.state.GetConnection()
+	l.state.SetRotation(connection)
+	_, _ = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	outcome, _ = l.statusManager.ApplyStatus(ctx, constants.Running)
+	return outcome, nil
+}
+
+type RotationTickerRunner interface {
+	RunRotationTicker(ctx context.Context, done chan<- struct{})
+}
+
+// RunRotationTicker rotates the VPN server periodically if the
+// rotation period setting is set, and only if the VPN is running.
+func (l *Loop) RunRotationTicker(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	settings := l.GetSettings()
+	if *settings.RotationPeriod == 0 {
+		return
+	}
+
+	timer := time.NewTimer(*settings.RotationPeriod)
+	for {
+		select {
+		case <-ctx.Done():
+			if !timer.Stop() {
+				<-timer.C
+			}
+			return
+		case <-timer.C:
+			if l.GetStatus() == constants.Running {
+				l.logger.Info("rotating VPN server")
+				_, _ = l.Rotate(ctx)
+			}
+			settings := l.GetSettings()
+			if *settings.RotationPeriod == 0 {
+				return
+			}
+			timer.Reset(*settings.RotationPeriod)
+		}
+	}
+}
+
+// filterProviderServers returns the servers data where the servers of
+// the VPN provider are filtered using the filter function given.
+// The servers data is returned unchanged if no server matches the
+// server selection once filtered.
+func (l *Loop) filterProviderServers(allServers models.AllServers,
+	vpnSettings settings.VPN, filter func(servers []models.Server) []models.Server,
+	filterName string) models.AllServers {
+	filtered, err := filterServers(allServers, vpnSettings, filter)
+	if err != nil {
+		l.logger.Warn("ignoring " + filterName + " since no other server is available: " +
+			err.Error())
+		return allServers
+	}
+	return filtered
+}
+
+// filterServers returns the servers data where the servers of the
+// VPN provider are filtered using the filter function given, and an
+// error if no server matches the server selection once filtered.
+func filterServers(allServers models.AllServers, vpnSettings settings.VPN,
+	filter func(servers []models.Server) []models.Server) (
+	filtered models.AllServers, err error) {
+	providerName := *vpnSettings.Provider.Name
+	providerServers := allServers.ServersByProvider(providerName)
+	if providerServers == nil {
+		return allServers, nil
+	}
+
+	// filter must return a new slice, the current one being shared with the state.
+	providerServers.Servers = filter(providerServers.Servers)
+
+	providerConf := provider.New(providerName, allServers, nil, nil, time.Now)
+	_, err = providerConf.GetConnection(vpnSettings.Provider.ServerSelection)
+	if err != nil {
+		return filtered, err
+	}
+	return allServers, nil
+}
+
+// excludeConnection returns a filter function removing
+// the servers corresponding to the connection given.
+func excludeConnection(connection models.Connection) func(servers []models.Server) []models.Server {
+	return func(servers []models.Server) (filtered []models.Server) {
+		filtered = make([]models.Server, 0, len(servers))
+		for _, server := range servers {
+			if !serverHasConnection(server, connection) {
+				filtered = append(filtered, server)
+			}
+		}
+		return filtered
+	}
+}
+
+func serverHasConnection(server models.Server, connection models.Connection) bool {
+	if connection.Hostname != "" &&
+		(server.Hostname == connection.Hostname || server.ServerName == connection.Hostname) {
+		return true
+	}
+	for _, ip := range server.IPs {
+		if ip.Equal(connection.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package vpn
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/loopstate"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/gluetun/internal/vpn/state"
+	"github.com/qdm12/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_serverHasConnection(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		server     models.Server
+		connection models.Connection
+		has        bool
+	}{
+		"empty connection": {
+			server: models.Server{
+				Hostname: "a.com",
+				IPs:      []net.IP{{1, 2, 3, 4}},
+			},
+		},
+		"matching IP address": {
+			server: models.Server{
+				IPs: []net.IP{{1, 2, 3, 4}, {5, 6, 7, 8}},
+			},
+			connection: models.Connection{IP: net.IP{5, 6, 7, 8}},
+			has:        true,
+		},
+		"matching hostname": {
+			server: models.Server{
+				Hostname: "a.com",
+				IPs:      []net.IP{{1, 2, 3, 4}},
+			},
+			connection: models.Connection{
+				IP:       net.IP{9, 9, 9, 9},
+				Hostname: "a.com",
+			},
+			has: true,
+		},
+		"matching server name": {
+			server: models.Server{
+				ServerName: "server1",
+			},
+			connection: models.Connection{Hostname: "server1"},
+			has:        true,
+		},
+		"no match": {
+			server: models.Server{
+				Hostname: "a.com",
+				IPs:      []net.IP{{1, 2, 3, 4}},
+			},
+			connection: models.Connection{
+				IP:       net.IP{5, 6, 7, 8},
+				Hostname: "b.com",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			has := serverHasConnection(testCase.server, testCase.connection)
+
+			assert.Equal(t, testCase.has, has)
+		})
+	}
+}
+
+// newTestLoop returns a loop in the running status where the run loop
+// is faked to always reach the running status once started, together
+// with a counter of the number of starts.
+func newTestLoop(t *testing.T, vpnSettings settings.VPN) (
+	loop *Loop, starts *int32) {
+	t.Helper()
+
+	start := make(chan struct{})
+	running := make(chan models.LoopStatus)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	logger := log.New(log.SetWriters(io.Discard))
+	statusManager := loopstate.New(constants.Running, start, running,
+		stop, stopped, "vpn", events.New(logger))
+
+	loop = &Loop{
+		statusManager: statusManager,
+		state:         state.New(statusManager, vpnSettings, models.AllServers{}),
+		logger:        logger,
+	}
+
+	starts = new(int32)
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-start:
+				atomic.AddInt32(starts, 1)
+				running <- constants.Running
+			case <-stop:
+				stopped <- struct{}{}
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-runDone
+	})
+
+	return loop, starts
+}
+
+func Test_Loop_Rotate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not running", func(t *testing.T) {
+		t.Parallel()
+
+		loop, starts := newTestLoop(t, settings.VPN{})
+		loop.statusManager.SetStatus(constants.Stopped)
+
+		outcome, err := loop.Rotate(context.Background())
+
+		assert.ErrorIs(t, err, ErrNotRunning)
+		assert.EqualError(t, err, "VPN is not running: status is stopped")
+		assert.Empty(t, outcome)
+		assert.Equal(t, int32(0), atomic.LoadInt32(starts))
+		_, rotating := loop.state.PopRotation()
+		assert.False(t, rotating)
+	})
+
+	t.Run("current server excluded", func(t *testing.T) {
+		t.Parallel()
+
+		loop, starts := newTestLoop(t, settings.VPN{})
+		connection := models.Connection{IP: net.IPv4(1, 2, 3, 4), Hostname: "a.com"}
+		loop.state.SetConnection(connection, "tun0")
+
+		outcome, err := loop.Rotate(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "running", outcome)
+		assert.Equal(t, int32(1), atomic.LoadInt32(starts))
+		excluded, rotating := loop.state.PopRotation()
+		assert.True(t, rotating)
+		assert.Equal(t, connection, excluded)
+	})
+}
+
+func Test_Loop_RunRotationTicker(t *testing.T) {
+	t.Parallel()
+
+	durationPtr := func(d time.Duration) *time.Duration { return &d }
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		loop, starts := newTestLoop(t, settings.VPN{RotationPeriod: durationPtr(0)})
+		done := make(chan struct{})
+
+		loop.RunRotationTicker(context.Background(), done)
+
+		_, ok := <-done
+		assert.False(t, ok)
+		assert.Equal(t, int32(0), atomic.LoadInt32(starts))
+	})
+
+	t.Run("period read on each reset", func(t *testing.T) {
+		t.Parallel()
+
+		loop, starts := newTestLoop(t, settings.VPN{RotationPeriod: durationPtr(time.Millisecond)})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan struct{})
+
+		go loop.RunRotationTicker(ctx, done)
+
+		const minimumRotations = 2
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(starts) >= minimumRotations
+		}, time.Second, time.Millisecond)
+
+		// Disabling the rotation period stops the ticker
+		// once its current period elapses.
+		loop.state.SetSettings(ctx, settings.VPN{RotationPeriod: durationPtr(0)})
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("rotation ticker did not stop")
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		loop, starts := newTestLoop(t, settings.VPN{RotationPeriod: durationPtr(time.Hour)})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go loop.RunRotationTicker(ctx, done)
+		cancel()
+
+		<-done
+		assert.Equal(t, int32(0), atomic.LoadInt32(starts))
+	})
+}
+
+func Test_filterServers(t *testing.T) {
+	t.Parallel()
+
+	var allSettings settings.Settings
+	allSettings.SetDefaults()
+	vpnSettings := allSettings.VPN
+	providerName := providers.Mullvad
+	vpnSettings.Provider.Name = &providerName
+
+	serverA := models.Server{VPN: constants.OpenVPN, Hostname: "a.com",
+		IPs: []net.IP{{1, 1, 1, 1}}, UDP: true}
+	serverB := models.Server{VPN: constants.OpenVPN, Hostname: "b.com",
+		IPs: []net.IP{{2, 2, 2, 2}}, UDP: true}
+	current := models.Connection{IP: net.IP{1, 1, 1, 1}, Hostname: "a.com"}
+
+	testCases := map[string]struct {
+		servers    []models.Server
+		filtered   []models.Server
+		errWrapped error
+		errMessage string
+	}{
+		"current server excluded": {
+			servers:  []models.Server{serverA, serverB},
+			filtered: []models.Server{serverB},
+		},
+		"no alternative server": {
+			servers:    []models.Server{serverA},
+			errWrapped: utils.ErrNoServerFound,
+			errMessage: "no server found: for VPN openvpn; protocol udp; encryption preset strong",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			allServers := models.AllServers{
+				Mullvad: models.Servers{Servers: testCase.servers},
+			}
+
+			filtered, err := filterServers(allServers, vpnSettings,
+				excludeConnection(current))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.filtered, filtered.Mullvad.Servers)
+			// The servers given are left unchanged.
+			assert.Len(t, allServers.Mullvad.Servers, len(testCase.servers))
+		})
+	}
+}
@@ -2,9 +2,12 @@ package vpn
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/log"
 )
@@ -26,31 +29,52 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		return
 	}
 
+	// The last known good server is only tried first
+	// on the first connection since the program started.
+	preferLastConnection := true
 	for ctx.Err() == nil {
 		settings, allServers := l.state.GetSettingsAndServers()
+		settings = l.applyProfile(settings)
+		settings = l.applyTCPFallback(settings)
+		if preferLastConnection {
+			preferLastConnection = false
+			allServers = l.preferLastConnection(allServers, settings)
+		}
+		if excluded, rotating := l.state.PopRotation(); rotating {
+			allServers = l.filterProviderServers(allServers, settings,
+				excludeConnection(excluded), "rotation")
+		}
 
-		providerConf := provider.New(*settings.Provider.Name, allServers, time.Now)
+		providerConf := provider.New(*settings.Provider.Name, allServers,
+			l.latencyProber, l.blocklist, time.Now)
 
 		portForwarding := *settings.Provider.PortForwarding.Enabled
 		var vpnRunner vpnRunner
-		var serverName, vpnInterface string
+		var connection models.Connection
+		var vpnInterface string
 		var err error
 		subLogger := l.logger.New(log.SetComponent(settings.Type))
 		if settings.Type == constants.OpenVPN {
 			vpnInterface = settings.OpenVPN.Interface
-			vpnRunner, serverName, err = setupOpenVPN(ctx, l.fw,
+			vpnRunner, connection, err = setupOpenVPN(ctx, l.fw,
 				l.openvpnConf, providerConf, settings, l.starter, subLogger)
 		} else { // Wireguard
 			vpnInterface = settings.Wireguard.Interface
-			vpnRunner, serverName, err = setupWireguard(ctx, l.netLinker, l.fw, providerConf, settings, subLogger)
+			vpnRunner, connection, err = setupWireguard(ctx, l.netLinker, l.fw, providerConf, settings, subLogger)
 		}
 		if err != nil {
 			l.crashed(ctx, err)
 			continue
 		}
+		if l.blocklist.Blocked(connection) {
+			l.logger.Warn("ignoring blocklist since no other server is available")
+		}
+		l.logSelection(settings.Provider.ServerSelection, connection)
+		l.state.SetConnection(connection, vpnInterface)
 		tunnelUpData := tunnelUpData{
 			portForwarding: portForwarding,
-			serverName:     serverName,
+			connection:     connection,
+			serverName:     connection.Hostname,
 			portForwarder:  providerConf,
 			vpnIntf:        vpnInterface,
 		}
@@ -97,6 +121,15 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			case err := <-waitError: // unexpected error
 				close(waitError)
 
+				if errors.Is(err, openvpn.ErrTLSNegotiationFailed) {
+					blockErr := l.blockServer(connection, err.Error())
+					if blockErr != nil {
+						l.logger.Error("cannot block server: " + blockErr.Error())
+					}
+				}
+
+				l.failOverIfDue()
+
 				l.statusManager.Lock() // prevent SetStatus from running in parallel
 
 				l.cleanup(context.Background(), portForwarding)
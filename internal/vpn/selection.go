@@ -0,0 +1,27 @@
+package vpn
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+func (l *Loop) logSelection(selection settings.ServerSelection,
+	connection models.Connection) {
+	if selection.Strategy == constants.SelectionRandom ||
+		len(selection.TargetIP) > 0 {
+		return
+	}
+
+	message := "server " + connection.IP.String() + " picked with the " +
+		selection.Strategy + " strategy"
+	latency, ok := l.latencyProber.Latency(connection)
+	if ok && latency > 0 {
+		message += " (latency " + latency.String() + ")"
+	} else {
+		// The latency strategies fall back on a random pick
+		// if none of the servers probed replied.
+		message += " (no reply to the latency probes, picked randomly)"
+	}
+	l.logger.Info(message)
+}
@@ -0,0 +1,26 @@
+package state
+
+import "github.com/qdm12/gluetun/internal/models"
+
+type ConnectionGetSetter interface {
+	GetConnection() (connection models.Connection, vpnInterface string)
+	SetConnection(connection models.Connection, vpnInterface string)
+}
+
+// GetConnection returns the current VPN connection and
+// VPN network interface name. They are empty if the VPN
+// is not connected.
+func (s *State) GetConnection() (connection models.Connection,
+	vpnInterface string) {
+	s.connectionMu.RLock()
+	defer s.connectionMu.RUnlock()
+	return s.connection, s.vpnInterface
+}
+
+func (s *State) SetConnection(connection models.Connection,
+	vpnInterface string) {
+	s.connectionMu.Lock()
+	defer s.connectionMu.Unlock()
+	s.connection = connection
+	s.vpnInterface = vpnInterface
+}
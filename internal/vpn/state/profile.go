@@ -0,0 +1,46 @@
+package state
+
+import "time"
+
+type ProfileGetSetter interface {
+	SetProfileIndex(index int)
+	GetProfileIndex() (index int)
+	SetProfileFailing(now time.Time) (since time.Time)
+	ResetProfileFailing()
+}
+
+// SetProfileIndex sets the index of the VPN profile to use,
+// where 0 is the primary profile defined by the VPN settings
+// and i is the failover profile at index i-1. It also resets
+// the failing time of the profile and the TCP fallback.
+func (s *State) SetProfileIndex(index int) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.profileIndex = index
+	s.profileFailingSince = time.Time{}
+	s.tcpFallback = false
+}
+
+func (s *State) GetProfileIndex() (index int) {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.profileIndex
+}
+
+// SetProfileFailing records the current VPN profile is failing,
+// and returns the time since it has been failing.
+func (s *State) SetProfileFailing(now time.Time) (since time.Time) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	if s.profileFailingSince.IsZero() {
+		s.profileFailingSince = now
+	}
+	return s.profileFailingSince
+}
+
+// ResetProfileFailing records the current VPN profile works.
+func (s *State) ResetProfileFailing() {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.profileFailingSince = time.Time{}
+}
@@ -0,0 +1,20 @@
+package state
+
+type RecoveryGetSetter interface {
+	SetTCPFallback(enabled bool)
+	GetTCPFallback() (enabled bool)
+}
+
+// SetTCPFallback sets whether OpenVPN should use TCP
+// instead of UDP, regardless of the settings.
+func (s *State) SetTCPFallback(enabled bool) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.tcpFallback = enabled
+}
+
+func (s *State) GetTCPFallback() (enabled bool) {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.tcpFallback
+}
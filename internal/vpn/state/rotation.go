@@ -0,0 +1,28 @@
+package state
+
+import "github.com/qdm12/gluetun/internal/models"
+
+type RotationSetPopper interface {
+	SetRotation(excluded models.Connection)
+	PopRotation() (excluded models.Connection, ok bool)
+}
+
+// SetRotation records the connection to exclude when
+// picking a connection for the next VPN start.
+func (s *State) SetRotation(excluded models.Connection) {
+	s.connectionMu.Lock()
+	defer s.connectionMu.Unlock()
+	s.rotationExcluded = excluded
+	s.rotating = true
+}
+
+// PopRotation returns the connection to exclude set with SetRotation
+// and true if a rotation is pending, and clears the pending rotation.
+func (s *State) PopRotation() (excluded models.Connection, ok bool) {
+	s.connectionMu.Lock()
+	defer s.connectionMu.Unlock()
+	excluded, ok = s.rotationExcluded, s.rotating
+	s.rotationExcluded = models.Connection{}
+	s.rotating = false
+	return excluded, ok
+}
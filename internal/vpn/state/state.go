@@ -2,6 +2,7 @@ package state
 
 import (
 	"sync"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/loopstate"
@@ -13,6 +14,10 @@ var _ Manager = (*State)(nil)
 type Manager interface {
 	SettingsGetSetter
 	ServersGetterSetter
+	ConnectionGetSetter
+	RotationSetPopper
+	RecoveryGetSetter
+	ProfileGetSetter
 	GetSettingsAndServers() (vpn settings.VPN, allServers models.AllServers)
 }
 
@@ -28,11 +33,20 @@ func New(statusApplier loopstate.Applier,
 type State struct {
 	statusApplier loopstate.Applier
 
-	vpn        settings.VPN
-	settingsMu sync.RWMutex
+	vpn                 settings.VPN
+	tcpFallback         bool
+	profileIndex        int
+	profileFailingSince time.Time
+	settingsMu          sync.RWMutex
 
 	allServers   models.AllServers
 	allServersMu sync.RWMutex
+
+	connection       models.Connection
+	vpnInterface     string
+	rotationExcluded models.Connection
+	rotating         bool
+	connectionMu     sync.RWMutex
 }
 
 func (s *State) GetSettingsAndServers() (vpn settings.VPN,
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
@@ -29,6 +30,12 @@ func (s *State) SetSettings(ctx context.Context, vpn settings.VPN) (
 		return "settings left unchanged"
 	}
 	s.vpn = vpn
+	// Settings changed by the user take precedence
+	// over the TCP fallback of the recovery and the
+	// failover profile in use.
+	s.tcpFallback = false
+	s.profileIndex = 0
+	s.profileFailingSince = time.Time{}
 	s.settingsMu.Unlock()
 	_, _ = s.statusApplier.ApplyStatus(ctx, constants.Stopped)
 	outcome, _ = s.statusApplier.ApplyStatus(ctx, constants.Running)
@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/version"
 )
 
 type tunnelUpData struct {
+	connection models.Connection
 	// Port forwarding
 	portForwarding bool
 	vpnIntf        string
@@ -19,6 +22,9 @@ type tunnelUpData struct {
 func (l *Loop) onTunnelUp(ctx context.Context, data tunnelUpData) {
 	l.client.CloseIdleConnections()
 
+	l.publisher.Publish("vpn", events.TypeTunnelUp,
+		tunnelData(data.connection, data.vpnIntf))
+
 	for _, vpnPort := range l.vpnInputPorts {
 		err := l.fw.SetAllowedPort(ctx, vpnPort, data.vpnIntf)
 		if err != nil {
@@ -47,3 +53,12 @@ func (l *Loop) onTunnelUp(ctx context.Context, data tunnelUpData) {
 		l.logger.Error(err.Error())
 	}
 }
+
+func tunnelData(connection models.Connection, vpnInterface string) events.TunnelData {
+	return events.TunnelData{
+		VPNType:    connection.Type,
+		Interface:  vpnInterface,
+		ServerIP:   connection.IP,
+		ServerName: connection.Hostname,
+	}
+}